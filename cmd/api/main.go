@@ -3,9 +3,17 @@ package main
 import (
 	"log"
 
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/router"
+	"github.com/duker221/teamly/internal/services/contentfilter"
+	"github.com/duker221/teamly/internal/services/discord"
 	"github.com/duker221/teamly/internal/services/email"
+	"github.com/duker221/teamly/internal/services/notifications"
+	"github.com/duker221/teamly/internal/services/storage"
+	"github.com/duker221/teamly/internal/services/telegram"
+	"github.com/duker221/teamly/internal/services/webhooks"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -21,26 +29,63 @@ func main() {
 
 	// Инициализация конфигурации JWT
 	utils.LoadTokenConfig()
+	config.LoadSigningConfig()
+
+	// Инициализация настроек откликов, отзывов, фильтра, уведомлений, почты и интеграций
+	config.LoadResponseConfig()
+	config.LoadReviewConfig()
+	config.LoadContentFilterConfig()
+	config.LoadNotificationConfig()
+	config.LoadEmailConfig()
+	config.LoadWebhookConfig()
+	config.LoadTelegramConfig()
+	config.LoadDiscordConfig()
+	config.LoadStorageConfig()
+	config.LoadAvatarConfig()
+	config.LoadSteamConfig()
 
 	// Инициализация базы данных
 	database.InitDB()
 
+	// Инициализация хранилища загруженных файлов
+	if err := storage.Init(); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Инициализация автофильтра контента
+	if err := contentfilter.Init(); err != nil {
+		log.Fatalf("Failed to initialize content filter: %v", err)
+	}
+
 	// Инициализация email сервиса
 	if err := email.InitEmailService(); err != nil {
 		log.Printf("Warning: Email service initialization failed: %v", err)
 	}
+	email.StartOutboxWorker()
+
+	// Фоновые задачи уведомлений: истекающие заявки и рассылка писем
+	notifications.StartExpiryWatcher()
+	notifications.StartEmailWorker()
+
+	// Доставка исходящих вебхуков
+	webhooks.StartDeliveryWorker()
+
+	// Telegram-бот: регистрация webhook и отправка уведомлений
+	telegram.RegisterWebhook()
+	telegram.StartNotifier()
+
+	// Анонсы заявок в каналах Discord
+	discord.StartWorker()
+
+	// Удаление файлов, на которые не осталось ссылок
+	storage.StartCleanup()
 
 	// Создание Fiber приложения
 	webApp := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+		// Все ошибки отдаются конвертом {code, message, details} на языке пользователя
+		ErrorHandler: apperror.Handler,
+		// Запас сверх лимита аватара на остальные поля multipart-формы
+		BodyLimit: max(config.AvatarConfig.MaxBytes+1<<20, fiber.DefaultBodyLimit),
 	})
 
 	// Middleware для логирования
@@ -0,0 +1,165 @@
+package apperror
+
+import "github.com/gofiber/fiber/v2"
+
+// Общие ошибки запроса
+var (
+	ErrInternal         = New(fiber.StatusInternalServerError, "internal_error")
+	ErrBadRequest       = New(fiber.StatusBadRequest, "bad_request")
+	ErrRouteNotFound    = New(fiber.StatusNotFound, "route_not_found")
+	ErrInvalidBody      = New(fiber.StatusBadRequest, "invalid_body")
+	ErrInvalidID        = New(fiber.StatusBadRequest, "invalid_id")
+	ErrInvalidValue     = New(fiber.StatusBadRequest, "invalid_value")
+	ErrFieldRequired    = New(fiber.StatusBadRequest, "field_required")
+	ErrTooShort         = New(fiber.StatusBadRequest, "too_short")
+	ErrTooLong          = New(fiber.StatusBadRequest, "too_long")
+	ErrInvalidDate      = New(fiber.StatusBadRequest, "invalid_date")
+	ErrSelfAction       = New(fiber.StatusBadRequest, "self_action")
+	ErrTooManyRequests  = New(fiber.StatusTooManyRequests, "too_many_requests")
+	ErrTooManyAttempts  = New(fiber.StatusTooManyRequests, "too_many_attempts")
+	ErrRecaptchaMissing = New(fiber.StatusBadRequest, "recaptcha_missing")
+	ErrRecaptchaFailed  = New(fiber.StatusForbidden, "recaptcha_failed")
+)
+
+// Авторизация и доступ
+var (
+	ErrUnauthorized       = New(fiber.StatusUnauthorized, "unauthorized")
+	ErrAccessDenied       = New(fiber.StatusForbidden, "access_denied")
+	ErrModeratorRequired  = New(fiber.StatusForbidden, "moderator_required")
+	ErrAdminRequired      = New(fiber.StatusForbidden, "admin_required")
+	ErrAccountSuspended   = New(fiber.StatusForbidden, "account_suspended")
+	ErrEmailNotFound      = New(fiber.StatusNotFound, "email_not_found")
+	ErrIncorrectPassword  = New(fiber.StatusUnauthorized, "incorrect_password")
+	ErrEmailTaken         = New(fiber.StatusConflict, "email_taken")
+	ErrNicknameTaken      = New(fiber.StatusConflict, "nickname_taken")
+	ErrInvalidToken       = New(fiber.StatusBadRequest, "invalid_token")
+	ErrUnsupportedLocale  = New(fiber.StatusBadRequest, "unsupported_locale")
+	ErrNotOwnProfile      = New(fiber.StatusForbidden, "not_own_profile")
+	ErrUserNotFound       = New(fiber.StatusNotFound, "user_not_found")
+	ErrCannotSuspendStaff = New(fiber.StatusForbidden, "cannot_suspend_staff")
+	ErrNotSuspended       = New(fiber.StatusBadRequest, "not_suspended")
+)
+
+// Заявки и вопросы к ним
+var (
+	ErrApplicationNotFound  = New(fiber.StatusNotFound, "application_not_found")
+	ErrApplicationInactive  = New(fiber.StatusBadRequest, "application_inactive")
+	ErrApplicationFull      = New(fiber.StatusConflict, "application_full")
+	ErrNotApplicationAuthor = New(fiber.StatusForbidden, "not_application_author")
+	ErrInvalidPlayerLimits  = New(fiber.StatusBadRequest, "invalid_player_limits")
+	ErrShareCodeRequired    = New(fiber.StatusForbidden, "share_code_required")
+	ErrShareCodeInvalid     = New(fiber.StatusNotFound, "share_code_invalid")
+	ErrGameNotFound         = New(fiber.StatusNotFound, "game_not_found")
+	ErrQuestionsLocked      = New(fiber.StatusBadRequest, "questions_locked")
+	ErrTooManyQuestions     = New(fiber.StatusBadRequest, "too_many_questions")
+	ErrQuestionInvalidType  = New(fiber.StatusBadRequest, "question_invalid_type")
+	ErrQuestionNoPrompt     = New(fiber.StatusBadRequest, "question_prompt_required")
+	ErrQuestionFewOptions   = New(fiber.StatusBadRequest, "question_options_required")
+	ErrQuestionInvalidRange = New(fiber.StatusBadRequest, "question_invalid_range")
+	ErrApplicationLimit     = New(fiber.StatusTooManyRequests, "application_limit")
+)
+
+// Отклики, ответы на вопросы и приглашения
+var (
+	ErrResponseNotFound        = New(fiber.StatusNotFound, "response_not_found")
+	ErrResponseWithdrawn       = New(fiber.StatusBadRequest, "response_withdrawn")
+	ErrResponseNotWithdrawable = New(fiber.StatusBadRequest, "response_not_withdrawable")
+	ErrNotResponseOwner        = New(fiber.StatusForbidden, "not_response_owner")
+	ErrAlreadyResponded        = New(fiber.StatusBadRequest, "already_responded")
+	ErrReapplyCooldown         = New(fiber.StatusBadRequest, "reapply_cooldown")
+	ErrAlreadyMember           = New(fiber.StatusBadRequest, "already_member")
+	ErrNotEnoughSlots          = New(fiber.StatusConflict, "not_enough_slots")
+	ErrCannotRespond           = New(fiber.StatusForbidden, "cannot_respond")
+	ErrAnswerRequired          = New(fiber.StatusBadRequest, "answer_required")
+	ErrAnswerSingleChoice      = New(fiber.StatusBadRequest, "answer_single_choice")
+	ErrAnswerInvalidChoice     = New(fiber.StatusBadRequest, "answer_invalid_choice")
+	ErrAnswerTooSmall          = New(fiber.StatusBadRequest, "answer_too_small")
+	ErrAnswerTooLarge          = New(fiber.StatusBadRequest, "answer_too_large")
+	ErrAnswerUnknownQuestion   = New(fiber.StatusBadRequest, "answer_unknown_question")
+	ErrFilterUnknownQuestion   = New(fiber.StatusBadRequest, "filter_unknown_question")
+	ErrFilterRangeUnsupported  = New(fiber.StatusBadRequest, "filter_range_unsupported")
+	ErrFilterInvalidNumber     = New(fiber.StatusBadRequest, "filter_invalid_number")
+	ErrInvitationNotFound      = New(fiber.StatusNotFound, "invitation_not_found")
+	ErrInvitationNotPending    = New(fiber.StatusBadRequest, "invitation_not_pending")
+	ErrNotInvitee              = New(fiber.StatusForbidden, "not_invitee")
+	ErrNotInviter              = New(fiber.StatusForbidden, "not_inviter")
+	ErrAlreadyInvited          = New(fiber.StatusBadRequest, "already_invited")
+	ErrCannotInvite            = New(fiber.StatusForbidden, "cannot_invite")
+	ErrCannotAcceptInvitation  = New(fiber.StatusForbidden, "cannot_accept_invitation")
+)
+
+// Сообщения, отзывы, посещаемость
+var (
+	ErrConversationNotFound = New(fiber.StatusNotFound, "conversation_not_found")
+	ErrCannotMessageUser    = New(fiber.StatusForbidden, "cannot_message_user")
+	ErrReviewNotFound       = New(fiber.StatusNotFound, "review_not_found")
+	ErrNotReviewOwner       = New(fiber.StatusForbidden, "not_review_owner")
+	ErrReviewLocked         = New(fiber.StatusForbidden, "review_locked")
+	ErrAlreadyReviewed      = New(fiber.StatusConflict, "already_reviewed")
+	ErrNotTeammate          = New(fiber.StatusForbidden, "not_teammate")
+	ErrNotRosterMember      = New(fiber.StatusForbidden, "not_roster_member")
+	ErrSessionNotEnded      = New(fiber.StatusBadRequest, "session_not_ended")
+	ErrAttendanceConflict   = New(fiber.StatusConflict, "attendance_conflict")
+	ErrNotBlocked           = New(fiber.StatusNotFound, "not_blocked")
+)
+
+// Модерация и фильтр контента
+var (
+	ErrContentRejected       = New(fiber.StatusBadRequest, "content_rejected")
+	ErrReportNotFound        = New(fiber.StatusNotFound, "report_not_found")
+	ErrReportTargetNotFound  = New(fiber.StatusNotFound, "report_target_not_found")
+	ErrReportResolved        = New(fiber.StatusBadRequest, "report_resolved")
+	ErrActionTargetMismatch  = New(fiber.StatusBadRequest, "action_target_mismatch")
+	ErrNotificationNotFound  = New(fiber.StatusNotFound, "notification_not_found")
+	ErrOutboxEmailNotFound   = New(fiber.StatusNotFound, "outbox_email_not_found")
+	ErrEmailNotRetryable     = New(fiber.StatusBadRequest, "email_not_retryable")
+	ErrEmailTemplateNotFound = New(fiber.StatusNotFound, "email_template_not_found")
+)
+
+// Вебхуки
+var (
+	ErrWebhookNotFound         = New(fiber.StatusNotFound, "webhook_not_found")
+	ErrWebhookDeliveryNotFound = New(fiber.StatusNotFound, "webhook_delivery_not_found")
+	ErrWebhookLimit            = New(fiber.StatusBadRequest, "webhook_limit")
+	ErrInvalidWebhookURL       = New(fiber.StatusBadRequest, "invalid_webhook_url")
+	ErrDeliveryNotRetryable    = New(fiber.StatusBadRequest, "delivery_not_retryable")
+)
+
+// Игровые профили
+var (
+	ErrGameProfileNotFound = New(fiber.StatusNotFound, "game_profile_not_found")
+	ErrGameProfileExists   = New(fiber.StatusConflict, "game_profile_exists")
+	ErrGameProfileLimit    = New(fiber.StatusBadRequest, "game_profile_limit")
+	ErrTooManyGameRoles    = New(fiber.StatusBadRequest, "too_many_game_roles")
+)
+
+// Steam
+var (
+	ErrSteamNotLinked      = New(fiber.StatusNotFound, "steam_not_linked")
+	ErrSteamStatsDisabled  = New(fiber.StatusServiceUnavailable, "steam_stats_disabled")
+	ErrSteamRefreshTooSoon = New(fiber.StatusTooManyRequests, "steam_refresh_too_soon")
+	ErrSteamUnavailable    = New(fiber.StatusBadGateway, "steam_unavailable")
+)
+
+// Telegram-бот
+var (
+	ErrTelegramDisabled  = New(fiber.StatusServiceUnavailable, "telegram_disabled")
+	ErrTelegramNotLinked = New(fiber.StatusNotFound, "telegram_not_linked")
+)
+
+// Анонсы в Discord
+var (
+	ErrDiscordChannelNotFound = New(fiber.StatusNotFound, "discord_channel_not_found")
+	ErrInvalidDiscordWebhook  = New(fiber.StatusBadRequest, "invalid_discord_webhook")
+)
+
+// Загрузка файлов
+var (
+	ErrFileTooLarge     = New(fiber.StatusRequestEntityTooLarge, "file_too_large")
+	ErrUnsupportedImage = New(fiber.StatusUnsupportedMediaType, "unsupported_image")
+	ErrImageTooLarge    = New(fiber.StatusBadRequest, "image_too_large")
+	ErrImageTooSmall    = New(fiber.StatusBadRequest, "image_too_small")
+	ErrAvatarNotSet     = New(fiber.StatusNotFound, "avatar_not_set")
+	ErrMediaNotFound    = New(fiber.StatusNotFound, "media_not_found")
+	ErrMediaLinkInvalid = New(fiber.StatusForbidden, "media_link_invalid")
+)
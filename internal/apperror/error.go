@@ -0,0 +1,107 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Code - стабильный машиночитаемый код ошибки, по которому фронтенд выбирает реакцию
+type Code string
+
+// Error - ошибка API из каталога: HTTP статус, код и детали для клиента.
+// Текст сообщения не хранится в ошибке, а переводится под язык запроса при ответе
+type Error struct {
+	Status  int
+	Code    Code
+	Details fiber.Map
+	// cause - внутренняя причина для логов, клиенту не отдается
+	cause error
+}
+
+// codes - все объявленные коды, для проверки переводов при старте
+var codes []Code
+
+// New объявляет ошибку каталога. Для кода должен быть перевод в locales/*.json
+func New(status int, code Code) *Error {
+	codes = append(codes, code)
+	return &Error{Status: status, Code: code}
+}
+
+// Error - сообщение на английском, для логов
+func (e *Error) Error() string {
+	return e.Message("en")
+}
+
+// Message - сообщение на языке locale с подстановкой деталей
+func (e *Error) Message(locale string) string {
+	return translate(e.Code, locale, e.Details)
+}
+
+// Unwrap открывает внутреннюю причину для errors.Is/As
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is сравнивает ошибки по коду, чтобы errors.Is работал и для копий с деталями
+func (e *Error) Is(target error) bool {
+	var other *Error
+	return errors.As(target, &other) && other.Code == e.Code
+}
+
+// With возвращает копию ошибки с добавленной деталью
+func (e *Error) With(key string, value any) *Error {
+	return e.WithDetails(fiber.Map{key: value})
+}
+
+// WithDetails возвращает копию ошибки с добавленными деталями
+func (e *Error) WithDetails(details fiber.Map) *Error {
+	merged := make(fiber.Map, len(e.Details)+len(details))
+	for key, value := range e.Details {
+		merged[key] = value
+	}
+	for key, value := range details {
+		merged[key] = value
+	}
+
+	copied := *e
+	copied.Details = merged
+	return &copied
+}
+
+// Wrap возвращает копию ошибки с внутренней причиной
+func (e *Error) Wrap(cause error) *Error {
+	copied := *e
+	copied.cause = cause
+	return &copied
+}
+
+// Internal - внутренняя ошибка сервера. Клиент видит только общий код,
+// а операция попадает в лог обработчика ошибок
+func Internal(operation string) *Error {
+	return ErrInternal.Wrap(errors.New("failed to " + operation))
+}
+
+// Field - ошибка, относящаяся к полю запроса
+func Field(err *Error, field string) *Error {
+	return err.With("field", field)
+}
+
+// Required - не заполнены обязательные поля запроса
+func Required(fields ...string) *Error {
+	return ErrFieldRequired.With("fields", fields)
+}
+
+// InvalidValue - недопустимое значение поля, allowed - допустимые значения
+func InvalidValue(field string, allowed ...string) *Error {
+	err := Field(ErrInvalidValue, field)
+	if len(allowed) > 0 {
+		err = err.With("allowed", allowed)
+	}
+	return err
+}
+
+// InvalidID - некорректный UUID в параметре или теле запроса
+func InvalidID(field string) *Error {
+	return Field(ErrInvalidID, field)
+}
@@ -0,0 +1,72 @@
+package apperror
+
+import (
+	"errors"
+	"log"
+
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler - ErrorHandler для Fiber. Любая ошибка превращается в конверт
+// {code, message, details}, сообщение переводится на язык пользователя
+func Handler(c *fiber.Ctx, err error) error {
+	appErr := From(err)
+	if appErr.Status >= fiber.StatusInternalServerError {
+		log.Printf("[%s %s] %v", c.Method(), c.Path(), causeOf(appErr, err))
+	}
+
+	body := fiber.Map{
+		"code":    appErr.Code,
+		"message": appErr.Message(Locale(c)),
+	}
+	if len(appErr.Details) > 0 {
+		body["details"] = appErr.Details
+	}
+	return c.Status(appErr.Status).JSON(body)
+}
+
+// From приводит произвольную ошибку к ошибке каталога.
+// Ошибки самого Fiber (неизвестный маршрут, слишком большое тело) сохраняют свой статус
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		switch {
+		case fiberErr.Code == fiber.StatusNotFound:
+			return ErrRouteNotFound
+		case fiberErr.Code >= fiber.StatusInternalServerError:
+			return ErrInternal.Wrap(err)
+		}
+		bad := ErrBadRequest.With("reason", fiberErr.Message)
+		bad.Status = fiberErr.Code
+		return bad
+	}
+
+	return ErrInternal.Wrap(err)
+}
+
+func causeOf(appErr *Error, err error) error {
+	if appErr.cause != nil {
+		return appErr.cause
+	}
+	return err
+}
+
+// Locale - язык ответа: сохраненный в профиле у авторизованного пользователя,
+// иначе первый поддерживаемый из Accept-Language
+func Locale(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("userID").(string); ok && userID != "" {
+		var user models.User
+		if err := database.DB.Select("id", "locale").Where("id = ?", userID).First(&user).Error; err == nil && user.Locale != "" {
+			return utils.NormalizeLocale(user.Locale)
+		}
+	}
+	return utils.LocaleFromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+}
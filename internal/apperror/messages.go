@@ -0,0 +1,36 @@
+package apperror
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/duker221/teamly/internal/i18n"
+	"github.com/duker221/teamly/internal/utils"
+)
+
+// Переводы сообщений: по JSON-файлу на язык, ключ - код ошибки,
+// значение - text/template строка, в которую подставляются детали ошибки
+//
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var messages = i18n.MustLoad("apperror", localeFS, template.FuncMap{
+	"join": func(values []string) string { return strings.Join(values, ", ") },
+})
+
+// Каждый код каталога должен быть переведен на все поддерживаемые языки
+func init() {
+	for _, locale := range utils.SupportedLocales {
+		for _, code := range codes {
+			if !messages.Has(locale, string(code)) {
+				panic(fmt.Sprintf("apperror: no %s message for code %q", locale, code))
+			}
+		}
+	}
+}
+
+func translate(code Code, locale string, details map[string]any) string {
+	return messages.T(locale, string(code), details)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"log"
+	"sort"
+	"strconv"
+)
+
+type AvatarsConfig struct {
+	// Максимальный размер загружаемого файла в байтах
+	MaxBytes int
+	// Максимум пикселей исходника - защита от "бомб" вида 50000x50000
+	MaxPixels int
+	// Стороны квадратных миниатюр по убыванию; первая используется как avatar_url
+	Sizes []int
+	// Качество JPEG при перекодировании (1-100)
+	JPEGQuality int
+}
+
+var AvatarConfig AvatarsConfig
+
+func LoadAvatarConfig() {
+	AvatarConfig = AvatarsConfig{
+		MaxBytes:    getInt("AVATAR_MAX_BYTES", 8<<20),
+		MaxPixels:   getInt("AVATAR_MAX_PIXELS", 40_000_000),
+		Sizes:       getSizes("AVATAR_SIZES", []int{512, 128, 64}),
+		JPEGQuality: getInt("AVATAR_JPEG_QUALITY", 85),
+	}
+	if AvatarConfig.JPEGQuality < 1 || AvatarConfig.JPEGQuality > 100 {
+		AvatarConfig.JPEGQuality = 85
+	}
+}
+
+// getSizes читает список размеров через запятую и сортирует по убыванию
+func getSizes(key string, fallback []int) []int {
+	var sizes []int
+	for _, item := range getList(key) {
+		size, err := strconv.Atoi(item)
+		if err != nil || size < 16 || size > 2048 {
+			log.Printf("Warning: invalid %s item %q, using defaults", key, item)
+			return fallback
+		}
+		sizes = append(sizes, size)
+	}
+	if len(sizes) == 0 {
+		return fallback
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	return sizes
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ContentFiltersConfig struct {
+	Enabled bool
+	// Пути к файлам со словами (по одному на строку); пусто - встроенные списки
+	WordlistRU string
+	WordlistEN string
+	// Что делать при срабатывании: "reject", "flag" или "off"
+	ProfanityAction  string
+	ContactsAction   string
+	SimilarityAction string
+	// Домены, ссылки на которые не считаются контактами (например, discord.gg)
+	AllowedDomains []string
+	// Порог похожести (0..1) и какие заявки автора сравниваются с новой
+	SimilarityThreshold float64
+	SimilarityWindow    time.Duration
+	SimilarityLookback  int
+}
+
+var ContentFilterConfig ContentFiltersConfig
+
+func LoadContentFilterConfig() {
+	ContentFilterConfig = ContentFiltersConfig{
+		Enabled:             os.Getenv("CONTENT_FILTER_ENABLED") != "false",
+		WordlistRU:          os.Getenv("CONTENT_FILTER_WORDLIST_RU"),
+		WordlistEN:          os.Getenv("CONTENT_FILTER_WORDLIST_EN"),
+		ProfanityAction:     getString("CONTENT_FILTER_PROFANITY_ACTION", "reject"),
+		ContactsAction:      getString("CONTENT_FILTER_CONTACTS_ACTION", "flag"),
+		SimilarityAction:    getString("CONTENT_FILTER_SIMILARITY_ACTION", "reject"),
+		AllowedDomains:      getList("CONTENT_FILTER_ALLOWED_DOMAINS"),
+		SimilarityThreshold: getFloat("CONTENT_FILTER_SIMILARITY_THRESHOLD", 0.85),
+		SimilarityWindow:    getDuration("CONTENT_FILTER_SIMILARITY_WINDOW", 72*time.Hour),
+		SimilarityLookback:  getInt("CONTENT_FILTER_SIMILARITY_LOOKBACK", 20),
+	}
+}
+
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getList читает список через запятую
+func getList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strings.ToLower(item))
+		}
+	}
+	return items
+}
+
+// getFloat читает дробное число из окружения
+func getFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %g", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+type DiscordAnnouncementsConfig struct {
+	// Адрес Discord API - для локальной разработки можно указать фейковый сервер
+	APIURL string
+	// Как часто синхронизировать анонсы и сколько сообщений за проход
+	PollInterval time.Duration
+	BatchSize    int
+	// После стольких неудачных попыток анонс помечается failed
+	MaxAttempts int
+	// Экспоненциальная задержка между попытками: RetryBase * 2^(попытка-1), но не больше RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Таймаут одного запроса к Discord
+	Timeout time.Duration
+}
+
+var DiscordConfig DiscordAnnouncementsConfig
+
+func LoadDiscordConfig() {
+	DiscordConfig = DiscordAnnouncementsConfig{
+		APIURL:       strings.TrimRight(getString("DISCORD_API_URL", "https://discord.com/api"), "/"),
+		PollInterval: getDuration("DISCORD_INTERVAL", 15*time.Second),
+		BatchSize:    getInt("DISCORD_BATCH_SIZE", 20),
+		MaxAttempts:  getInt("DISCORD_MAX_ATTEMPTS", 8),
+		RetryBase:    getDuration("DISCORD_RETRY_BASE", 30*time.Second),
+		RetryMax:     getDuration("DISCORD_RETRY_MAX", time.Hour),
+		Timeout:      getDuration("DISCORD_TIMEOUT", 10*time.Second),
+	}
+	if DiscordConfig.BatchSize < 1 {
+		DiscordConfig.BatchSize = 1
+	}
+}
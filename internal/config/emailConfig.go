@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+type EmailOutboxConfig struct {
+	// Как часто воркер забирает письма из очереди и сколько за раз
+	PollInterval time.Duration
+	BatchSize    int
+	// После стольких неудачных попыток письмо уходит в dead
+	MaxAttempts int
+	// Экспоненциальная задержка между попытками: RetryBase * 2^(попытка-1), но не больше RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Сколько хранить доставленные и недоставленные письма (0 - не удалять)
+	Retention time.Duration
+}
+
+var EmailConfig EmailOutboxConfig
+
+func LoadEmailConfig() {
+	EmailConfig = EmailOutboxConfig{
+		PollInterval: getDuration("EMAIL_OUTBOX_INTERVAL", 10*time.Second),
+		BatchSize:    getInt("EMAIL_OUTBOX_BATCH_SIZE", 20),
+		MaxAttempts:  getInt("EMAIL_MAX_ATTEMPTS", 8),
+		RetryBase:    getDuration("EMAIL_RETRY_BASE", 30*time.Second),
+		RetryMax:     getDuration("EMAIL_RETRY_MAX", time.Hour),
+		Retention:    getDuration("EMAIL_OUTBOX_RETENTION", 30*24*time.Hour),
+	}
+	if EmailConfig.BatchSize < 1 {
+		EmailConfig.BatchSize = 1
+	}
+}
@@ -0,0 +1,6 @@
+package config
+
+// FrontendURL - адрес фронтенда, на страницы которого ведут ссылки во внешних сообщениях
+func FrontendURL() string {
+	return getString("FRONTEND_URL", "http://localhost:3000")
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+type NotificationsConfig struct {
+	// За сколько до конца прайм-тайма предупреждать автора об истечении заявки
+	ExpiryLead time.Duration
+	// Как часто искать истекающие заявки
+	ExpiryCheckInterval time.Duration
+	// Как часто отправлять письма-уведомления; мгновенные события за этот интервал приходят одним письмом
+	EmailInterval time.Duration
+	// Через сколько после сообщения писать о нем на почту, если оно так и не прочитано
+	EmailMessageDelay time.Duration
+	// Как часто отправлять дайджест
+	DigestInterval time.Duration
+}
+
+var NotificationConfig NotificationsConfig
+
+func LoadNotificationConfig() {
+	NotificationConfig = NotificationsConfig{
+		ExpiryLead:          getDuration("NOTIFICATION_EXPIRY_LEAD", time.Hour),
+		ExpiryCheckInterval: getDuration("NOTIFICATION_EXPIRY_CHECK_INTERVAL", 5*time.Minute),
+		EmailInterval:       getDuration("NOTIFICATION_EMAIL_INTERVAL", 2*time.Minute),
+		EmailMessageDelay:   getDuration("NOTIFICATION_EMAIL_MESSAGE_DELAY", 15*time.Minute),
+		DigestInterval:      getDuration("NOTIFICATION_DIGEST_INTERVAL", 24*time.Hour),
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+type ResponsesConfig struct {
+	// Через сколько после отклонения/отзыва можно откликнуться повторно
+	ReapplyCooldown time.Duration
+	// Сколько вопросов автор может добавить к заявке
+	MaxQuestions int
+}
+
+var ResponseConfig ResponsesConfig
+
+func LoadResponseConfig() {
+	ResponseConfig = ResponsesConfig{
+		ReapplyCooldown: getDuration("RESPONSE_REAPPLY_COOLDOWN", 24*time.Hour),
+		MaxQuestions:    getInt("APPLICATION_MAX_QUESTIONS", 10),
+	}
+}
+
+// getDuration читает длительность вида "24h", "30m" из окружения
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// getInt читает целое число из окружения
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
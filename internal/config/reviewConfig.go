@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type ReviewsConfig struct {
+	// Сколько времени после создания отзыв можно редактировать
+	EditWindow time.Duration
+}
+
+var ReviewConfig ReviewsConfig
+
+func LoadReviewConfig() {
+	ReviewConfig = ReviewsConfig{
+		EditWindow: getDuration("REVIEW_EDIT_WINDOW", 7*24*time.Hour),
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+)
+
+type SigningKeys struct {
+	// Подпись share-кодов скрытых заявок
+	ShareCode []byte
+	// Подпись ссылок отписки в письмах
+	Unsubscribe []byte
+	// Подпись временных ссылок на файлы драйвера local
+	StorageURL []byte
+	// Подпись параметра state в ссылке на вход через Steam
+	SteamState []byte
+}
+
+var SigningConfig SigningKeys
+
+// LoadSigningConfig загружает ключи HMAC-подписей ссылок и токенов.
+// Без ключа подпись можно подделать, поэтому API без него не запускается
+func LoadSigningConfig() {
+	SigningConfig = SigningKeys{
+		ShareCode:   signingKey("SHARE_CODE_SECRET", "share-code"),
+		Unsubscribe: signingKey("EMAIL_UNSUBSCRIBE_SECRET", "unsubscribe"),
+		StorageURL:  signingKey("STORAGE_SIGNING_SECRET", "storage-url"),
+		SteamState:  signingKey("STEAM_STATE_SECRET", "steam-state"),
+	}
+}
+
+// signingKey - ключ подписи для назначения purpose или остановка запуска, если его нет
+func signingKey(envKey, purpose string) []byte {
+	key, err := deriveSigningKey(envKey, purpose)
+	if err != nil {
+		log.Fatalf("Failed to load signing key: %v", err)
+	}
+	return key
+}
+
+// deriveSigningKey берет ключ из envKey, а если он не задан - выводит его из SIGNING_SECRET
+// отдельно для каждого назначения: утечка одной подписи не открывает остальные.
+// Ключ JWT для этого не используется
+func deriveSigningKey(envKey, purpose string) ([]byte, error) {
+	if value := os.Getenv(envKey); value != "" {
+		return []byte(value), nil
+	}
+
+	master := os.Getenv("SIGNING_SECRET")
+	if master == "" {
+		return nil, fmt.Errorf("%s or SIGNING_SECRET must be set", envKey)
+	}
+	mac := hmac.New(sha256.New, []byte(master))
+	mac.Write([]byte("teamly:" + purpose))
+	return mac.Sum(nil), nil
+}
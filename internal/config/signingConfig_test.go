@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveSigningKeyRequiresSecret(t *testing.T) {
+	t.Setenv("SIGNING_SECRET", "")
+	t.Setenv("SHARE_CODE_SECRET", "")
+
+	if key, err := deriveSigningKey("SHARE_CODE_SECRET", "share-code"); err == nil {
+		t.Fatalf("deriveSigningKey without secrets = %q, want error", key)
+	}
+}
+
+func TestDeriveSigningKeySeparatesPurposes(t *testing.T) {
+	t.Setenv("SIGNING_SECRET", "master")
+	t.Setenv("SHARE_CODE_SECRET", "")
+	t.Setenv("EMAIL_UNSUBSCRIBE_SECRET", "")
+
+	share, err := deriveSigningKey("SHARE_CODE_SECRET", "share-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsubscribe, err := deriveSigningKey("EMAIL_UNSUBSCRIBE_SECRET", "unsubscribe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(share, unsubscribe) {
+		t.Fatal("keys for different purposes must differ")
+	}
+	if bytes.Equal(share, []byte("master")) {
+		t.Fatal("derived key must not be the master secret itself")
+	}
+}
+
+func TestDeriveSigningKeyPrefersExplicitKey(t *testing.T) {
+	t.Setenv("SIGNING_SECRET", "master")
+	t.Setenv("SHARE_CODE_SECRET", "explicit")
+
+	key, err := deriveSigningKey("SHARE_CODE_SECRET", "share-code")
+	if err != nil || string(key) != "explicit" {
+		t.Fatalf("deriveSigningKey = %q, %v; want explicit key", key, err)
+	}
+}
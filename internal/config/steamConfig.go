@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type SteamAPIConfig struct {
+	// Ключ Steam Web API; без него аккаунт привязывается, но библиотека не загружается
+	APIKey string
+	// Адреса Steam Web API и OpenID - для локальной разработки можно указать заглушку
+	APIURL    string
+	OpenIDURL string
+	// Публичный адрес GET /api/steam/callback, куда Steam возвращает пользователя
+	CallbackURL string
+	// Страница фронтенда, куда пользователь попадает после привязки
+	RedirectURL string
+	// Сколько считать загруженные из Steam данные свежими
+	CacheTTL time.Duration
+	// Не чаще скольких раз пользователь может обновить данные вручную
+	RefreshCooldown time.Duration
+	// Сколько действует ссылка на вход через Steam
+	LinkTTL time.Duration
+	// Таймаут одного запроса к Steam
+	Timeout time.Duration
+}
+
+var SteamConfig SteamAPIConfig
+
+func LoadSteamConfig() {
+	SteamConfig = SteamAPIConfig{
+		APIKey:          os.Getenv("STEAM_API_KEY"),
+		APIURL:          strings.TrimRight(getString("STEAM_API_URL", "https://api.steampowered.com"), "/"),
+		OpenIDURL:       getString("STEAM_OPENID_URL", "https://steamcommunity.com/openid/login"),
+		CallbackURL:     getString("STEAM_CALLBACK_URL", getString("API_URL", "http://localhost:3001")+"/api/steam/callback"),
+		RedirectURL:     getString("STEAM_REDIRECT_URL", FrontendURL()+"/settings"),
+		CacheTTL:        getDuration("STEAM_CACHE_TTL", 6*time.Hour),
+		RefreshCooldown: getDuration("STEAM_REFRESH_COOLDOWN", 10*time.Minute),
+		LinkTTL:         getDuration("STEAM_LINK_TTL", 15*time.Minute),
+		Timeout:         getDuration("STEAM_TIMEOUT", 10*time.Second),
+	}
+}
+
+// StatsEnabled - можно ли загружать библиотеку и время в играх
+func (c SteamAPIConfig) StatsEnabled() bool {
+	return c.APIKey != ""
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+type ObjectStorageConfig struct {
+	// Драйвер хранилища файлов: local или s3
+	Driver string
+	// Каталог для драйвера local
+	LocalDir string
+	// Публичный адрес, по которому раздаются файлы: <PublicURL>/<ключ>
+	PublicURL string
+
+	// S3-совместимое хранилище (AWS S3, MinIO и т.п.)
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// Адресация bucket в пути (endpoint/bucket/key), а не в поддомене - нужна для MinIO
+	S3PathStyle bool
+	// Таймаут одного запроса к хранилищу
+	S3Timeout time.Duration
+
+	// Как часто искать файлы, на которые не осталось ссылок (0 - не искать),
+	// и сколько файл должен пролежать без ссылок, прежде чем его удалят
+	CleanupInterval time.Duration
+	OrphanTTL       time.Duration
+}
+
+var StorageConfig ObjectStorageConfig
+
+func LoadStorageConfig() {
+	StorageConfig = ObjectStorageConfig{
+		Driver:          getString("STORAGE_DRIVER", "local"),
+		LocalDir:        getString("STORAGE_LOCAL_DIR", "uploads"),
+		S3Endpoint:      strings.TrimRight(getString("STORAGE_S3_ENDPOINT", "https://s3.amazonaws.com"), "/"),
+		S3Region:        getString("STORAGE_S3_REGION", "us-east-1"),
+		S3Bucket:        getString("STORAGE_S3_BUCKET", ""),
+		S3AccessKey:     getString("STORAGE_S3_ACCESS_KEY", ""),
+		S3SecretKey:     getString("STORAGE_S3_SECRET_KEY", ""),
+		S3PathStyle:     getString("STORAGE_S3_PATH_STYLE", "true") == "true",
+		S3Timeout:       getDuration("STORAGE_S3_TIMEOUT", 30*time.Second),
+		CleanupInterval: getDuration("STORAGE_CLEANUP_INTERVAL", 6*time.Hour),
+		OrphanTTL:       getDuration("STORAGE_ORPHAN_TTL", 24*time.Hour),
+	}
+
+	// По умолчанию файлы local раздает сам API, а файлы s3 - напрямую из bucket
+	publicURL := getString("API_URL", "http://localhost:3001") + "/media"
+	if StorageConfig.Driver == "s3" {
+		publicURL = StorageConfig.S3Endpoint + "/" + StorageConfig.S3Bucket
+		if endpoint, err := url.Parse(StorageConfig.S3Endpoint); err == nil && !StorageConfig.S3PathStyle {
+			publicURL = endpoint.Scheme + "://" + StorageConfig.S3Bucket + "." + endpoint.Host
+		}
+	}
+	StorageConfig.PublicURL = strings.TrimRight(getString("STORAGE_PUBLIC_URL", publicURL), "/")
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type TelegramBotConfig struct {
+	// Токен бота от @BotFather; пустой - интеграция выключена
+	BotToken string
+	// Имя бота без @ для ссылок t.me/<бот>?start=<код>
+	BotUsername string
+	// Адрес Bot API - для локальной разработки можно указать фейковый сервер
+	APIURL string
+	// Публичный адрес POST /api/telegram/webhook; если задан, регистрируется при старте
+	WebhookURL string
+	// Секрет, который Telegram присылает в X-Telegram-Bot-Api-Secret-Token.
+	// Без него обновления нельзя отличить от поддельных, и webhook выключен
+	WebhookSecret string
+	// Сколько действует одноразовый код привязки
+	LinkCodeTTL time.Duration
+	// Как часто отправлять в Telegram новые уведомления
+	PollInterval time.Duration
+	// Таймаут одного запроса к Bot API
+	Timeout time.Duration
+}
+
+var TelegramConfig TelegramBotConfig
+
+func LoadTelegramConfig() {
+	TelegramConfig = TelegramBotConfig{
+		BotToken:      os.Getenv("TELEGRAM_BOT_TOKEN"),
+		BotUsername:   strings.TrimPrefix(os.Getenv("TELEGRAM_BOT_USERNAME"), "@"),
+		APIURL:        strings.TrimRight(getString("TELEGRAM_API_URL", "https://api.telegram.org"), "/"),
+		WebhookURL:    os.Getenv("TELEGRAM_WEBHOOK_URL"),
+		WebhookSecret: os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+		LinkCodeTTL:   getDuration("TELEGRAM_LINK_CODE_TTL", 15*time.Minute),
+		PollInterval:  getDuration("TELEGRAM_INTERVAL", 5*time.Second),
+		Timeout:       getDuration("TELEGRAM_TIMEOUT", 10*time.Second),
+	}
+}
+
+// Enabled - настроен ли бот
+func (c TelegramBotConfig) Enabled() bool {
+	return c.BotToken != ""
+}
+
+// WebhookEnabled - принимать ли обновления на POST /api/telegram/webhook
+func (c TelegramBotConfig) WebhookEnabled() bool {
+	return c.Enabled() && c.WebhookSecret != ""
+}
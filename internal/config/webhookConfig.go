@@ -0,0 +1,41 @@
+package config
+
+import "time"
+
+type WebhooksConfig struct {
+	// Как часто воркер забирает доставки из очереди и сколько за раз
+	PollInterval time.Duration
+	BatchSize    int
+	// После стольких неудачных попыток доставка уходит в dead
+	MaxAttempts int
+	// Экспоненциальная задержка между попытками: RetryBase * 2^(попытка-1), но не больше RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Таймаут одного HTTP запроса к получателю
+	Timeout time.Duration
+	// Как часто искать истекшие заявки для события application.closed
+	ExpiryCheckInterval time.Duration
+	// Сколько вебхуков может завести обычный пользователь
+	MaxPerUser int
+	// Разрешить адреса в локальной сети и localhost - для разработки с локальным получателем
+	AllowPrivateNetworks bool
+}
+
+var WebhookConfig WebhooksConfig
+
+func LoadWebhookConfig() {
+	WebhookConfig = WebhooksConfig{
+		PollInterval:         getDuration("WEBHOOK_INTERVAL", 10*time.Second),
+		BatchSize:            getInt("WEBHOOK_BATCH_SIZE", 20),
+		MaxAttempts:          getInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		RetryBase:            getDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+		RetryMax:             getDuration("WEBHOOK_RETRY_MAX", time.Hour),
+		Timeout:              getDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		ExpiryCheckInterval:  getDuration("WEBHOOK_EXPIRY_CHECK_INTERVAL", 5*time.Minute),
+		MaxPerUser:           getInt("WEBHOOK_MAX_PER_USER", 10),
+		AllowPrivateNetworks: getString("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false") == "true",
+	}
+	if WebhookConfig.BatchSize < 1 {
+		WebhookConfig.BatchSize = 1
+	}
+}
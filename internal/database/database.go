@@ -63,12 +63,29 @@ func AutoMigrate() error {
 		&models.Game{},
 		&models.GameApplication{},
 		&models.ApplicationResponse{},
+		&models.ApplicationInvitation{},
+		&models.ApplicationQuestion{},
+		&models.ResponseAnswer{},
 		&models.Conversation{},
 		&models.Message{},
 		&models.PasswordResetToken{},
+		&models.Review{},
+		&models.AttendanceReport{},
+		&models.UserBlock{},
+		&models.Report{},
+		&models.ModerationAction{},
+		&models.Notification{},
+		&models.EmailPreference{},
+		&models.EmailOutbox{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.TelegramLinkCode{},
+		&models.DiscordChannel{},
+		&models.DiscordPost{},
+		&models.UserGameProfile{},
+		&models.SteamProfile{},
 		// &models.Listing{},
 		// &models.ListingGame{},
-		// &models.Review{},
 	)
 
 	if err != nil {
@@ -85,6 +102,11 @@ func AutoMigrate() error {
 		return fmt.Errorf("game seeding failed: %v", err)
 	}
 
+	// Steam ID известных игр - нужны для подтверждения часов из библиотеки Steam
+	if err := SeedSteamAppIDs(); err != nil {
+		return fmt.Errorf("steam app id seeding failed: %v", err)
+	}
+
 	log.Println("Migrations completed successfully")
 	return nil
 }
@@ -161,3 +183,25 @@ func SeedGames() error {
 	log.Printf("Successfully seeded %d games", len(games))
 	return nil
 }
+
+// steamAppIDs - ID приложений в Steam для игр из SeedGames (Valorant в Steam нет)
+var steamAppIDs = map[string]int{
+	"counter-strike-2":   730,
+	"dota-2":             570,
+	"apex-legends":       1172470,
+	"pubg-battlegrounds": 578080,
+}
+
+// SeedSteamAppIDs проставляет Steam ID играм, у которых он еще не задан.
+// Выполняется при каждом запуске, поэтому доходит и до уже заполненных баз
+func SeedSteamAppIDs() error {
+	for slug, appID := range steamAppIDs {
+		if err := DB.Model(&models.Game{}).
+			Where("slug = ? AND steam_app_id IS NULL", slug).
+			Where("NOT EXISTS (SELECT 1 FROM games WHERE steam_app_id = ?)", appID).
+			Update("steam_app_id", appID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
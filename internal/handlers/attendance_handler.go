@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rosterUserIDs - автор заявки и все принятые игроки
+func rosterUserIDs(db *gorm.DB, application *models.GameApplication) []uuid.UUID {
+	var ids []uuid.UUID
+	db.Model(&models.ApplicationResponse{}).
+		Where("application_id = ? AND status = ?", application.ID, models.StatusAccepted).
+		Pluck("user_id", &ids)
+	return append([]uuid.UUID{application.UserId}, ids...)
+}
+
+// ReportAttendance - отметить, пришел ли тиммейт на игру
+// POST /api/applications/:id/attendance
+func ReportAttendance(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	appUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("application_id")
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+		Status string `json:"status"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	reportedUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	status := models.AttendanceStatus(req.Status)
+	if !status.IsValid() {
+		return apperror.InvalidValue("status", "attended", "no_show")
+	}
+
+	if reportedUserID == userID {
+		return apperror.ErrSelfAction.With("action", "report_attendance")
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, appUUID).Error; err != nil {
+		return apperror.ErrApplicationNotFound
+	}
+
+	// Отмечать можно только после окончания игры
+	if time.Now().Before(application.PrimeTimeEnd) {
+		return apperror.ErrSessionNotEnded
+	}
+
+	if !isOnRoster(database.DB, &application, userID) || !isOnRoster(database.DB, &application, reportedUserID) {
+		return apperror.ErrNotTeammate
+	}
+
+	var report models.AttendanceReport
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Отметка читается под блокировкой строки: две параллельные смены статуса
+		// иначе обе перенесли бы ее между счетчиками и рассинхронизировали их
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("application_id = ? AND reporter_id = ? AND reported_user_id = ?", appUUID, userID, reportedUserID).
+			First(&report).Error
+
+		if err == gorm.ErrRecordNotFound {
+			report = models.AttendanceReport{
+				ApplicationID:  appUUID,
+				ReporterID:     userID,
+				ReportedUserID: reportedUserID,
+				Status:         status,
+			}
+			if err := tx.Create(&report).Error; err != nil {
+				return err
+			}
+			return changeAttendanceCounter(tx, reportedUserID, status, 1)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Отметку можно поменять - переносим ее между счетчиками
+		if report.Status == status {
+			return nil
+		}
+		oldStatus := report.Status
+		report.Status = status
+		if err := tx.Save(&report).Error; err != nil {
+			return err
+		}
+		if err := changeAttendanceCounter(tx, reportedUserID, oldStatus, -1); err != nil {
+			return err
+		}
+		return changeAttendanceCounter(tx, reportedUserID, status, 1)
+	})
+	if isUniqueViolation(err, "idx_attendance_pair") {
+		return apperror.ErrAttendanceConflict
+	}
+	if err != nil {
+		return apperror.Internal("save attendance report")
+	}
+
+	return c.JSON(report)
+}
+
+// GetApplicationAttendance - состав заявки и мои отметки по нему
+// GET /api/applications/:id/attendance
+func GetApplicationAttendance(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	appUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("application_id")
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, appUUID).Error; err != nil {
+		return apperror.ErrApplicationNotFound
+	}
+
+	if !isOnRoster(database.DB, &application, userID) {
+		return apperror.ErrNotRosterMember
+	}
+
+	var roster []models.User
+	database.DB.
+		Select("id", "nickname", "avatar_url", "attended_count", "no_show_count").
+		Where("id IN ?", rosterUserIDs(database.DB, &application)).
+		Find(&roster)
+
+	var reports []models.AttendanceReport
+	database.DB.
+		Where("application_id = ? AND reporter_id = ?", appUUID, userID).
+		Find(&reports)
+
+	return c.JSON(fiber.Map{
+		"roster":         roster,
+		"my_reports":     reports,
+		"can_report":     !time.Now().Before(application.PrimeTimeEnd),
+		"prime_time_end": application.PrimeTimeEnd,
+	})
+}
+
+// changeAttendanceCounter меняет attended_count/no_show_count пользователя на delta
+func changeAttendanceCounter(tx *gorm.DB, userID uuid.UUID, status models.AttendanceStatus, delta int) error {
+	column := status.CounterColumn()
+	return tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumn(column, gorm.Expr("GREATEST("+column+" + ?, 0)", delta)).Error
+}
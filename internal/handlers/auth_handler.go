@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/contentfilter"
+	"github.com/duker221/teamly/internal/services/suspension"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -33,23 +37,17 @@ func RegisterUser(c *fiber.Ctx) error {
 
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("[Register] Body parse error: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	if req.Email == "" || req.Password == "" || req.Nickname == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email, nickname and password are required",
-		})
+		return apperror.Required("email", "nickname", "password")
 	}
 
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		log.Printf("[Register] Hash password error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to hash password",
-		})
+		return apperror.Internal("hash password")
 	}
 
 	user := models.User{
@@ -57,6 +55,8 @@ func RegisterUser(c *fiber.Ctx) error {
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
 		Nickname:     req.Nickname,
+		// Язык писем берем из браузера, потом его можно сменить в профиле
+		Locale: utils.LocaleFromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage)),
 	}
 
 	res := database.DB.Create(&user)
@@ -64,18 +64,12 @@ func RegisterUser(c *fiber.Ctx) error {
 		log.Printf("[Register] DB create error: %v", res.Error)
 		// Check for duplicate email/nickname
 		if strings.Contains(res.Error.Error(), "idx_users_email") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": "Email already registered",
-			})
+			return apperror.ErrEmailTaken
 		}
 		if strings.Contains(res.Error.Error(), "idx_users_nickname") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": "Nickname already taken",
-			})
+			return apperror.ErrNicknameTaken
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create user",
-		})
+		return apperror.Internal("create user")
 	}
 
 	log.Printf("[Register] User created: %s", user.ID)
@@ -83,9 +77,7 @@ func RegisterUser(c *fiber.Ctx) error {
 	token, err := utils.GenerateToken(user.ID)
 	if err != nil {
 		log.Printf("[Register] Token generation error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate token",
-		})
+		return apperror.Internal("generate token")
 	}
 
 	log.Printf("[Register] Success for user: %s", user.Email)
@@ -103,37 +95,32 @@ func LoginUser(c *fiber.Ctx) error {
 	var req models.LoginRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	if req.Email == "" || req.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email and password are required",
-		})
+		return apperror.Required("email", "password")
 	}
 
 	user := models.User{}
 	database.DB.Where("email = ?", req.Email).First(&user)
 
 	if user.ID == uuid.Nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Email not found",
-		})
+		return apperror.ErrEmailNotFound
 	}
 
 	if !utils.ComparePassword(user.PasswordHash, req.Password) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Incorrect password",
-		})
+		return apperror.ErrIncorrectPassword
+	}
+
+	var suspended *suspension.Error
+	if errors.As(suspension.Of(&user), &suspended) {
+		return apperror.ErrAccountSuspended.WithDetails(suspended.Details())
 	}
 
 	token, err := utils.GenerateToken(user.ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate token",
-		})
+		return apperror.Internal("generate token")
 	}
 
 	// Устанавливаем HTTP-only cookie
@@ -149,20 +136,14 @@ func GetMe(c *fiber.Ctx) error {
 	// Получаем user ID из cookie или Authorization header
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "Unauthorized",
-			"details": err.Error(),
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	user := models.User{}
 	result := database.DB.Preload("Country").Where("id = ?", userID).First(&user)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error":   "User not found",
-			"details": result.Error.Error(),
-		})
+		return apperror.ErrUserNotFound
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -173,41 +154,36 @@ func GetMe(c *fiber.Ctx) error {
 func GetUserByID(c *fiber.Ctx) error {
 	userID := c.Params("id")
 	if userID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "User ID is required",
-		})
+		return apperror.Required("user_id")
 	}
 
 	parsedID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID format",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
 	user := models.User{}
-	result := database.DB.Preload("Country").Where("id = ?", parsedID).First(&user)
+	result := database.DB.Preload("Country").Scopes(withGameProfiles).Where("id = ?", parsedID).First(&user)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return apperror.ErrUserNotFound
 	}
 
 	// Получаем активные заявки пользователя
+	currentUserID, _ := utils.GetUserIDFromContext(c)
+
 	var applications []models.GameApplication
-	database.DB.
+	appsQuery := database.DB.
 		Preload("Game").
 		Preload("User").
-		Where("user_id = ? AND is_active = ?", parsedID, true).
-		Order("created_at DESC").
-		Find(&applications)
-
-	// Скрываем email при просмотре чужого профиля
-	currentUserID, _ := utils.GetUserIDFromContext(c)
+		Where("user_id = ? AND is_active = ?", parsedID, true)
 	if currentUserID != parsedID {
-		user.Email = ""
+		appsQuery = appsQuery.Scopes(feedVisible)
 	}
+	appsQuery.Order("created_at DESC").Find(&applications)
+
+	// Скрываем email и поля, закрытые настройками приватности, при просмотре чужого профиля
+	applyPrivacy(currentUserID, append(applicationUsers(applications), &user)...)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"user":         user,
@@ -238,17 +214,13 @@ func UpdateProfile(c *fiber.Ctx) error {
 	// Получаем user ID из cookie
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	// Проверяем что пользователь редактирует свой профиль
 	paramID := c.Params("id")
 	if paramID != "" && paramID != userID.String() {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "You can only update your own profile",
-		})
+		return apperror.ErrNotOwnProfile
 	}
 
 	type UpdateProfileRequest struct {
@@ -259,55 +231,77 @@ func UpdateProfile(c *fiber.Ctx) error {
 		BirthDate   *string  `json:"birth_date"`
 		Gender      *string  `json:"gender"`
 		Languages   []string `json:"languages"`
+		Locale      *string  `json:"locale"`
 	}
 
 	var req UpdateProfileRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	var user models.User
 	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return apperror.ErrUserNotFound
 	}
 
+	// Обновляем только переданные поля: счетчики отзывов и посещаемости и блокировку
+	// меняют другие запросы, и сохранение всей строки затерло бы их
+	var columns []string
 	if req.Discord != nil {
 		user.Discord = req.Discord
+		columns = append(columns, "discord")
 	}
 	if req.Telegram != nil {
 		user.Telegram = req.Telegram
+		columns = append(columns, "telegram")
 	}
 	if req.CountryCode != nil {
 		user.CountryCode = req.CountryCode
+		columns = append(columns, "country_code")
 	}
+	var filterResult contentfilter.Result
 	if req.Description != nil {
+		filterResult, err = checkContent(*req.Description, nil)
+		if err != nil {
+			return err
+		}
 		user.Description = req.Description
+		columns = append(columns, "description")
 	}
 	if req.BirthDate != nil && *req.BirthDate != "" {
 		parsed, err := time.Parse("2006-01-02", *req.BirthDate)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid birth_date format, expected YYYY-MM-DD",
-			})
+			return apperror.Field(apperror.ErrInvalidDate, "birth_date").With("format", "YYYY-MM-DD")
 		}
 		user.BirthDate = &models.Date{Time: parsed}
+		columns = append(columns, "birth_date")
 	}
 	if req.Gender != nil {
 		user.Gender = req.Gender
+		columns = append(columns, "gender")
 	}
 	if req.Languages != nil {
 		user.Languages = req.Languages
+		columns = append(columns, "languages")
+	}
+	if req.Locale != nil {
+		if !utils.IsSupportedLocale(*req.Locale) {
+			return apperror.ErrUnsupportedLocale
+		}
+		user.Locale = utils.NormalizeLocale(*req.Locale)
+		columns = append(columns, "locale")
 	}
 
 	// Сохраняем изменения
-	if err := database.DB.Save(&user).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update profile",
-		})
+	if len(columns) > 0 {
+		if err := database.DB.Model(&user).Select(columns).Updates(&user).Error; err != nil {
+			return apperror.Internal("update profile")
+		}
+	}
+
+	if user.Description != nil {
+		flagForReview(filterResult, models.ReportTargetUser, user.ID, user.ID,
+			fiber.Map{"nickname": user.Nickname, "description": *user.Description})
 	}
 
 	// Загружаем обновленные данные с Country
@@ -324,17 +318,13 @@ func UpdateProfile(c *fiber.Ctx) error {
 func GetWebSocketToken(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	// Генерируем токен (тот же что и для обычной авторизации)
 	token, err := utils.GenerateToken(userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate token",
-		})
+		return apperror.Internal("generate token")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/avatar"
+	"github.com/duker221/teamly/internal/services/storage"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// UploadAvatar - загрузка аватара (multipart, поле avatar). Файл перекодируется
+// в квадратные JPEG-миниатюры без метаданных
+// POST /api/auth/me/avatar
+func UploadAvatar(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		return apperror.Required("avatar")
+	}
+	maxBytes := config.AvatarConfig.MaxBytes
+	if fileHeader.Size > int64(maxBytes) {
+		return apperror.ErrFileTooLarge.With("max_bytes", maxBytes)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return apperror.Internal("read avatar")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxBytes)+1))
+	if err != nil {
+		return apperror.Internal("read avatar")
+	}
+	if len(data) > maxBytes {
+		return apperror.ErrFileTooLarge.With("max_bytes", maxBytes)
+	}
+
+	thumbnails, err := avatar.Process(data)
+	switch {
+	case errors.Is(err, avatar.ErrUnsupportedFormat):
+		return apperror.ErrUnsupportedImage.With("allowed", avatar.AllowedTypes)
+	case errors.Is(err, avatar.ErrTooManyPixels):
+		return apperror.ErrImageTooLarge.With("max_pixels", config.AvatarConfig.MaxPixels)
+	case errors.Is(err, avatar.ErrTooSmall):
+		return apperror.ErrImageTooSmall.With("min_side", avatar.MinSide)
+	case err != nil:
+		return apperror.Internal("process avatar")
+	}
+
+	// Ключи по содержимому: повторная загрузка того же файла не плодит копии,
+	// а старые миниатюры удалит очистка хранилища, когда на них не останется ссылок
+	keys := make([]string, 0, len(thumbnails))
+	for _, thumbnail := range thumbnails {
+		keys = append(keys, storage.ContentKey("avatars", thumbnail.Data, ".jpg"))
+	}
+
+	urls := make(map[string]string, len(thumbnails))
+	avatarURL := storage.Default.URL(keys[0])
+	// Загрузка и запись ссылок идут под блокировкой ключей: иначе очистка может
+	// удалить такой же файл, оставшийся без ссылок, сразу после его перезаписи
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := storage.LockKeys(tx, keys...); err != nil {
+			return apperror.Internal("store avatar")
+		}
+		for i, thumbnail := range thumbnails {
+			if err := storage.Default.Put(keys[i], bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), avatar.ContentType); err != nil {
+				log.Printf("Failed to store avatar %s: %v", keys[i], err)
+				return apperror.Internal("store avatar")
+			}
+			urls[strconv.Itoa(thumbnail.Size)] = storage.Default.URL(keys[i])
+		}
+
+		user := models.User{
+			ID:               userID,
+			AvatarURL:        &avatarURL,
+			AvatarThumbnails: urls,
+			AvatarKeys:       keys,
+		}
+		// Через структуру, а не map - иначе к jsonb-полям не применится сериализатор
+		result := tx.Model(&user).Select("avatar_url", "avatar_thumbnails", "avatar_keys").Updates(&user)
+		if result.Error != nil {
+			return apperror.Internal("update avatar")
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"avatar_url":        avatarURL,
+		"avatar_thumbnails": urls,
+	})
+}
+
+// DeleteAvatar - удалить аватар
+// DELETE /api/auth/me/avatar
+func DeleteAvatar(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "avatar_url").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.AvatarURL == nil {
+		return apperror.ErrAvatarNotSet
+	}
+
+	user.AvatarURL = nil
+	user.AvatarThumbnails = nil
+	user.AvatarKeys = nil
+	if err := database.DB.Model(&user).Select("avatar_url", "avatar_thumbnails", "avatar_keys").Updates(&user).Error; err != nil {
+		return apperror.Internal("delete avatar")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Avatar deleted",
+	})
+}
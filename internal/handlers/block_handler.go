@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// isBlocked - blockerID заблокировал blockedID
+func isBlocked(blockerID, blockedID uuid.UUID) bool {
+	var count int64
+	database.DB.Model(&models.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count)
+	return count > 0
+}
+
+// isBlockedEitherWay - хотя бы один из пользователей заблокировал другого
+func isBlockedEitherWay(a, b uuid.UUID) bool {
+	var count int64
+	database.DB.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", a, b, b, a).
+		Count(&count)
+	return count > 0
+}
+
+// blockedByMe - подзапрос ID пользователей, которых заблокировал userID
+func blockedByMe(userID uuid.UUID) *gorm.DB {
+	return database.DB.Model(&models.UserBlock{}).Select("blocked_id").Where("blocker_id = ?", userID)
+}
+
+// blockedMe - подзапрос ID пользователей, которые заблокировали userID
+func blockedMe(userID uuid.UUID) *gorm.DB {
+	return database.DB.Model(&models.UserBlock{}).Select("blocker_id").Where("blocked_id = ?", userID)
+}
+
+// GetMyBlocks - список заблокированных пользователей
+// GET /api/blocks
+func GetMyBlocks(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var blocks []models.UserBlock
+	if err := database.DB.
+		Preload("Blocked", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname", "avatar_url")
+		}).
+		Where("blocker_id = ?", userID).
+		Order("created_at DESC").
+		Find(&blocks).Error; err != nil {
+		return apperror.Internal("fetch blocks")
+	}
+
+	return c.JSON(blocks)
+}
+
+// BlockUser - заблокировать пользователя
+// POST /api/blocks
+func BlockUser(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	blockedID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	if blockedID == userID {
+		return apperror.ErrSelfAction.With("action", "block")
+	}
+
+	var blocked models.User
+	if err := database.DB.Select("id").First(&blocked, blockedID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	if isBlocked(userID, blockedID) {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "User is already blocked",
+		})
+	}
+
+	block := models.UserBlock{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	}
+	if err := database.DB.Create(&block).Error; err != nil {
+		return apperror.Internal("block user")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(block)
+}
+
+// UnblockUser - снять блокировку
+// DELETE /api/blocks/:userId
+func UnblockUser(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	blockedID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	result := database.DB.
+		Where("blocker_id = ? AND blocked_id = ?", userID, blockedID).
+		Delete(&models.UserBlock{})
+	if result.Error != nil {
+		return apperror.Internal("unblock user")
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotBlocked
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "User unblocked successfully",
+	})
+}
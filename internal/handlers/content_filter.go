@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/contentfilter"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// checkContent прогоняет текст через автофильтр. При reject возвращает ошибку content_rejected,
+// при flag - результат, по которому после сохранения вызывается flagForReview
+func checkContent(text string, previous []string) (contentfilter.Result, error) {
+	result := contentfilter.Run(contentfilter.Input{Text: text, Previous: previous})
+	if result.Action == contentfilter.ActionReject {
+		return result, apperror.ErrContentRejected.WithDetails(fiber.Map{"check": result.Check, "reason": result.Reason})
+	}
+	return result, nil
+}
+
+// flagForReview ставит сохраненный контент в очередь модерации от имени автофильтра.
+// Ошибка не мешает пользователю - контент уже сохранен
+func flagForReview(result contentfilter.Result, targetType models.ReportTargetType, targetID, ownerID uuid.UUID, snapshot fiber.Map) {
+	if result.Action != contentfilter.ActionFlag {
+		return
+	}
+
+	// Пока предыдущая автожалоба не разобрана, новую не создаем
+	var existing int64
+	database.DB.Model(&models.Report{}).
+		Where("reporter_id IS NULL AND target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportOpen).
+		Count(&existing)
+	if existing > 0 {
+		return
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("Failed to snapshot flagged %s %s: %v", targetType, targetID, err)
+		return
+	}
+
+	details := result.Check + ": " + result.Reason
+	report := models.Report{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetUserID:   ownerID,
+		ReasonCode:     models.ReasonAutoFlagged,
+		Details:        &details,
+		TargetSnapshot: string(data),
+		Status:         models.ReportOpen,
+	}
+	if err := database.DB.Create(&report).Error; err != nil {
+		log.Printf("Failed to flag %s %s for review: %v", targetType, targetID, err)
+	}
+}
+
+// recentApplicationTexts - тексты недавних заявок автора для проверки на повторы
+func recentApplicationTexts(userID, excludeID uuid.UUID) []string {
+	cfg := config.ContentFilterConfig
+
+	var applications []models.GameApplication
+	database.DB.
+		Select("title", "description").
+		Where("user_id = ? AND id <> ? AND created_at > ?", userID, excludeID, time.Now().Add(-cfg.SimilarityWindow)).
+		Order("created_at DESC").
+		Limit(cfg.SimilarityLookback).
+		Find(&applications)
+
+	texts := make([]string, 0, len(applications))
+	for _, application := range applications {
+		texts = append(texts, applicationText(application.Title, application.Description))
+	}
+	return texts
+}
+
+func applicationText(title, description string) string {
+	return title + "\n" + description
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
 	"github.com/gofiber/fiber/v2"
@@ -12,9 +13,7 @@ func GetAllCountries(c *fiber.Ctx) error {
 	result := database.DB.Find(&countries)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch countries",
-		})
+		return apperror.Internal("fetch countries")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -27,18 +26,14 @@ func GetAllCountries(c *fiber.Ctx) error {
 func GetCountriesByRegion(c *fiber.Ctx) error {
 	region := c.Params("region")
 	if region == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Region is required",
-		})
+		return apperror.Required("region")
 	}
 
 	var countries []models.Country
 	result := database.DB.Where("region = ?", region).Find(&countries)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch countries",
-		})
+		return apperror.Internal("fetch countries")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/discord"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetDiscordChannels - игры с подключенными каналами анонсов
+// GET /api/admin/discord-channels
+func GetDiscordChannels(c *fiber.Ctx) error {
+	var channels []models.DiscordChannel
+	if err := database.DB.Preload("Game").Order("created_at ASC").Find(&channels).Error; err != nil {
+		return apperror.Internal("fetch discord channels")
+	}
+
+	return c.JSON(fiber.Map{
+		"channels": channels,
+	})
+}
+
+// SetGameDiscordChannel - подключить или изменить канал анонсов игры.
+// Вебхук проверяется запросом к Discord до сохранения
+// PUT /api/admin/games/:id/discord
+func SetGameDiscordChannel(c *fiber.Ctx) error {
+	gameID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("game_id")
+	}
+
+	var game models.Game
+	if err := database.DB.Select("id").First(&game, gameID).Error; err != nil {
+		return apperror.ErrGameNotFound
+	}
+
+	var req struct {
+		WebhookURL *string `json:"webhook_url"`
+		Locale     *string `json:"locale"`
+		IsActive   *bool   `json:"is_active"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	var channel models.DiscordChannel
+	err = database.DB.Where("game_id = ?", gameID).First(&channel).Error
+	isNew := err == gorm.ErrRecordNotFound
+	if err != nil && !isNew {
+		return apperror.Internal("fetch discord channel")
+	}
+	if isNew {
+		if req.WebhookURL == nil {
+			return apperror.Required("webhook_url")
+		}
+		channel = models.DiscordChannel{GameID: gameID, Locale: utils.DefaultLocale, IsActive: true}
+	}
+
+	if req.WebhookURL != nil {
+		webhookID, token, err := discord.ParseWebhookURL(strings.TrimSpace(*req.WebhookURL))
+		if err != nil {
+			return apperror.ErrInvalidDiscordWebhook.With("reason", err.Error())
+		}
+		if err := discord.CheckWebhook(webhookID, token); err != nil {
+			return apperror.ErrInvalidDiscordWebhook.With("reason", err.Error())
+		}
+		channel.WebhookID = webhookID
+		channel.WebhookToken = token
+	}
+	if req.Locale != nil {
+		if !utils.IsSupportedLocale(*req.Locale) {
+			return apperror.InvalidValue("locale", utils.SupportedLocales...)
+		}
+		channel.Locale = utils.NormalizeLocale(*req.Locale)
+	}
+	if req.IsActive != nil {
+		channel.IsActive = *req.IsActive
+	}
+
+	if err := database.DB.Save(&channel).Error; err != nil {
+		return apperror.Internal("save discord channel")
+	}
+
+	status := fiber.StatusOK
+	if isNew {
+		status = fiber.StatusCreated
+	}
+	return c.Status(status).JSON(channel)
+}
+
+// DeleteGameDiscordChannel - отключить канал анонсов. Уже опубликованные сообщения остаются в Discord
+// DELETE /api/admin/games/:id/discord
+func DeleteGameDiscordChannel(c *fiber.Ctx) error {
+	gameID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("game_id")
+	}
+
+	var channel models.DiscordChannel
+	if err := database.DB.Where("game_id = ?", gameID).First(&channel).Error; err != nil {
+		return apperror.ErrDiscordChannelNotFound
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("channel_id = ?", channel.ID).Delete(&models.DiscordPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&channel).Error
+	})
+	if err != nil {
+		return apperror.Internal("delete discord channel")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Discord channel disconnected",
+	})
+}
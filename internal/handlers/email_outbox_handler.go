@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/email"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetEmailOutbox - состояние очереди писем для админов
+// GET /api/admin/emails?status=dead&kind=password_reset&to=...&page=1&limit=50
+func GetEmailOutbox(c *fiber.Ctx) error {
+	query := database.DB.Model(&models.EmailOutbox{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if to := c.Query("to"); to != "" {
+		query = query.Where("recipient = ?", to)
+	}
+
+	var emails []models.EmailOutbox
+	page, err := paginate(c, query, &emails, pageLimits{Default: 50, Max: 100}, func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at DESC")
+	})
+	if err != nil {
+		return apperror.Internal("fetch emails")
+	}
+
+	// Сводка по статусам для всей очереди
+	var stats []struct {
+		Status models.EmailStatus `json:"status"`
+		Count  int64              `json:"count"`
+	}
+	database.DB.Model(&models.EmailOutbox{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&stats)
+
+	return c.JSON(fiber.Map{
+		"emails": emails,
+		"count":  len(emails),
+		"total":  page.Total,
+		"stats":  stats,
+	})
+}
+
+// RetryEmail - вернуть письмо из dead letter в очередь
+// POST /api/admin/emails/:id/retry
+func RetryEmail(c *fiber.Ctx) error {
+	emailID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("email_id")
+	}
+
+	var entry models.EmailOutbox
+	if err := database.DB.First(&entry, emailID).Error; err != nil {
+		return apperror.ErrOutboxEmailNotFound
+	}
+
+	// Письмо со стертым телом (например, со ссылкой сброса пароля) повторить нельзя
+	if entry.Status == models.EmailSent || entry.Status == models.EmailPending || entry.RedactedAt != nil {
+		return apperror.ErrEmailNotRetryable
+	}
+
+	if err := database.DB.Model(&entry).Updates(map[string]interface{}{
+		"status":          models.EmailPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error; err != nil {
+		return apperror.Internal("retry email")
+	}
+
+	return c.JSON(entry)
+}
+
+// PreviewEmail - шаблон письма на тестовых данных для проверки верстки и переводов
+// GET /api/admin/emails/preview/:name?locale=en&format=html|text
+func PreviewEmail(c *fiber.Ctx) error {
+	locale := c.Query("locale", utils.DefaultLocale)
+	if !utils.IsSupportedLocale(locale) {
+		return apperror.ErrUnsupportedLocale
+	}
+
+	msg, err := email.Preview(c.Params("name"), locale)
+	if err != nil {
+		return apperror.ErrEmailTemplateNotFound.With("templates", email.TemplateNames)
+	}
+
+	switch c.Query("format", "html") {
+	case "html":
+		c.Type("html", "utf-8")
+		return c.SendString(msg.HTML)
+	case "text":
+		c.Type("txt", "utf-8")
+		return c.SendString(msg.Text)
+	default:
+		return c.JSON(fiber.Map{
+			"subject": msg.Subject,
+			"html":    msg.HTML,
+			"text":    msg.Text,
+		})
+	}
+}
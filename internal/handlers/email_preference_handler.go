@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/notifications"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// setEmailModes сохраняет режимы писем пользователя (upsert по user_id + event_type)
+func setEmailModes(db *gorm.DB, userID uuid.UUID, modes map[models.NotificationType]models.EmailMode) error {
+	preferences := make([]models.EmailPreference, 0, len(modes))
+	for eventType, mode := range modes {
+		preferences = append(preferences, models.EmailPreference{
+			UserID:    userID,
+			EventType: eventType,
+			Mode:      mode,
+		})
+	}
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+// GetEmailPreferences - режимы писем по каждому типу событий
+// GET /api/auth/me/email-preferences
+func GetEmailPreferences(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	return c.JSON(fiber.Map{
+		"preferences": notifications.EmailModes(database.DB, userID),
+	})
+}
+
+// UpdateEmailPreferences - изменить режимы писем: {"preferences": {"new_response": "digest"}}
+// PUT /api/auth/me/email-preferences
+func UpdateEmailPreferences(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		Preferences map[string]string `json:"preferences"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	modes := make(map[models.NotificationType]models.EmailMode, len(req.Preferences))
+	for event, value := range req.Preferences {
+		eventType := models.NotificationType(event)
+		if !eventType.IsEmailEvent() {
+			return apperror.InvalidValue("event").With("value", event)
+		}
+		mode := models.EmailMode(value)
+		if !mode.IsValid() {
+			return apperror.InvalidValue("mode", "instant", "digest", "off")
+		}
+		modes[eventType] = mode
+	}
+
+	if err := setEmailModes(database.DB, userID, modes); err != nil {
+		return apperror.Internal("update email preferences")
+	}
+
+	return c.JSON(fiber.Map{
+		"preferences": notifications.EmailModes(database.DB, userID),
+	})
+}
+
+// UnsubscribeEmail - отписка по ссылке из письма, без авторизации.
+// Поддерживает one-click отписку почтовых клиентов (RFC 8058)
+// POST /api/email/unsubscribe?token=...
+func UnsubscribeEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		var req struct {
+			Token string `json:"token"`
+		}
+		c.BodyParser(&req)
+		token = req.Token
+	}
+
+	userID, event, err := utils.ParseUnsubscribeToken(token)
+	if err != nil {
+		return apperror.ErrInvalidToken
+	}
+
+	modes := make(map[models.NotificationType]models.EmailMode)
+	if event == utils.UnsubscribeAll {
+		for _, eventType := range models.EmailEventTypes {
+			modes[eventType] = models.EmailOff
+		}
+	} else {
+		eventType := models.NotificationType(event)
+		if !eventType.IsEmailEvent() {
+			return apperror.ErrInvalidToken
+		}
+		modes[eventType] = models.EmailOff
+	}
+
+	var user models.User
+	if err := database.DB.Select("id").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	if err := setEmailModes(database.DB, userID, modes); err != nil {
+		return apperror.Internal("unsubscribe")
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     "Unsubscribed successfully",
+		"event":       event,
+		"preferences": notifications.EmailModes(database.DB, userID),
+	})
+}
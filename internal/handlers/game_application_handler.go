@@ -3,8 +3,12 @@ package handlers
 import (
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/contentfilter"
+	"github.com/duker221/teamly/internal/services/suspension"
+	"github.com/duker221/teamly/internal/services/webhooks"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,6 +25,22 @@ type CreateGameApplicationRequest struct {
 	PrimeTimeEnd    time.Time `json:"prime_time_end"`
 	WithVoiceChat   bool      `json:"with_voice_chat"`
 	Platform        string    `json:"platform"`
+	Visibility      string    `json:"visibility"`
+	// nil - не менять вопросы при обновлении
+	Questions *[]QuestionRequest `json:"questions"`
+}
+
+// orderQuestions загружает вопросы заявки в порядке, заданном автором
+func orderQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// feedVisible оставляет только заявки, которые можно показывать в общих списках:
+// публичные, не скрытые модератором и не принадлежащие заблокированным авторам
+func feedVisible(db *gorm.DB) *gorm.DB {
+	return db.
+		Where("game_applications.visibility = ? AND game_applications.is_hidden = ?", models.VisibilityPublic, false).
+		Where("game_applications.user_id NOT IN (?)", suspension.Users())
 }
 
 // ApplicationWithUserResponse - заявка с информацией об отклике пользователя
@@ -37,62 +57,65 @@ func CreateGameApplication(c *fiber.Ctx) error {
 	// Получаем ID пользователя из контекста (должен быть установлен middleware)
 	userID := c.Locals("userID")
 	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "User not authenticated",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	parsedUserID, err := uuid.Parse(userID.(string))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
 	// Парсим тело запроса
 	var req CreateGameApplicationRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	// Валидация
 	if req.Title == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Title is required",
-		})
+		return apperror.Required("title")
 	}
 	if req.Description == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Description is required",
-		})
+		return apperror.Required("description")
 	}
 	if req.MaxPlayers < req.MinPlayers {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Max players must be greater than or equal to min players",
-		})
+		return apperror.ErrInvalidPlayerLimits
 	}
 	if req.MinPlayers < 1 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Min players must be at least 1",
-		})
+		return apperror.ErrInvalidPlayerLimits
+	}
+
+	visibility := models.VisibilityPublic
+	if req.Visibility != "" {
+		visibility = models.Visibility(req.Visibility)
+		if !visibility.IsValid() {
+			return apperror.InvalidValue("visibility", "public", "unlisted", "private")
+		}
+	}
+
+	filterResult, err := checkContent(applicationText(req.Title, req.Description), recentApplicationTexts(parsedUserID, uuid.Nil))
+	if err != nil {
+		return err
+	}
+
+	var questions []models.ApplicationQuestion
+	if req.Questions != nil {
+		questions, err = buildApplicationQuestions(*req.Questions)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Парсим GameID
 	parsedGameID, err := uuid.Parse(req.GameID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid game ID",
-		})
+		return apperror.InvalidID("game_id")
 	}
 
 	// Проверяем существование игры
 	var game models.Game
 	if err := database.DB.First(&game, parsedGameID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Game not found",
-		})
+		return apperror.ErrGameNotFound
 	}
 
 	// Создаем заявку
@@ -107,39 +130,59 @@ func CreateGameApplication(c *fiber.Ctx) error {
 		PrimeTimeEnd:   req.PrimeTimeEnd,
 		WithVoiceChat:  req.WithVoiceChat,
 		Platform:       models.Platform(req.Platform),
+		Visibility:     visibility,
+		Questions:      questions,
 		IsActive:       true,
 		IsFull:         false,
 	}
 
-	if err := database.DB.Create(&application).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create application",
-		})
+	// Для скрытых заявок сразу готовим share-код
+	if visibility != models.VisibilityPublic {
+		nonce, err := utils.NewShareNonce()
+		if err != nil {
+			return apperror.Internal("generate share code")
+		}
+		application.ShareNonce = nonce
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&application).Error; err != nil {
+			return err
+		}
+		return webhooks.DispatchApplication(tx, models.WebhookApplicationCreated, application.ID, "")
+	})
+	if err != nil {
+		return apperror.Internal("create application")
 	}
 
+	flagForReview(filterResult, models.ReportTargetApplication, application.ID, parsedUserID,
+		fiber.Map{"title": application.Title, "description": application.Description})
+
 	// Загружаем связанные данные
-	database.DB.Preload("Game").Preload("User").First(&application, application.ID)
+	database.DB.Preload("Game").Preload("User").Preload("Questions", orderQuestions).First(&application, application.ID)
+	applyPrivacy(parsedUserID, &application.User)
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	result := fiber.Map{
 		"message":     "Application created successfully",
 		"application": application,
-	})
+	}
+	if application.ShareNonce != "" {
+		result["share_code"] = utils.GenerateShareCode(application.ID, application.ShareNonce)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
 }
 
 // GetUserApplications получает все заявки пользователя
 func GetUserApplications(c *fiber.Ctx) error {
 	userID := c.Locals("userID")
 	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "User not authenticated",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	parsedUserID, err := uuid.Parse(userID.(string))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
 	var applications []models.GameApplication
@@ -151,10 +194,9 @@ func GetUserApplications(c *fiber.Ctx) error {
 		Find(&applications)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch applications",
-		})
+		return apperror.Internal("fetch applications")
 	}
+	applyPrivacy(parsedUserID, applicationUsers(applications)...)
 
 	// Получаем количество pending откликов для каждой заявки
 	applicationIDs := make([]uuid.UUID, len(applications))
@@ -201,32 +243,34 @@ func GetUserApplications(c *fiber.Ctx) error {
 func GetApplicationsByUserID(c *fiber.Ctx) error {
 	userIDParam := c.Params("id")
 	if userIDParam == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "User ID is required",
-		})
+		return apperror.Required("user_id")
 	}
 
 	parsedUserID, err := uuid.Parse(userIDParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID format",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
-	var applications []models.GameApplication
-	result := database.DB.
+	query := database.DB.
 		Preload("Game").
 		Preload("User").
-		Where("user_id = ? AND is_active = ?", parsedUserID, true).
-		Order("created_at DESC").
-		Find(&applications)
+		Where("user_id = ? AND is_active = ?", parsedUserID, true)
+
+	// Скрытые заявки видит только сам автор
+	if currentUserID, _ := utils.GetUserIDFromContext(c); currentUserID != parsedUserID {
+		query = query.Scopes(feedVisible)
+	}
+
+	var applications []models.GameApplication
+	result := query.Order("created_at DESC").Find(&applications)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch applications",
-		})
+		return apperror.Internal("fetch applications")
 	}
 
+	currentUserID, _ := utils.GetUserIDFromContext(c)
+	applyPrivacy(currentUserID, applicationUsers(applications)...)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"applications": applications,
 		"count":        len(applications),
@@ -247,8 +291,16 @@ func GetAllApplications(c *fiber.Ctx) error {
 	query := database.DB.
 		Preload("Game").
 		Preload("User").
+		Scopes(feedVisible).
 		Where("is_active = ?", true)
 
+	// Скрываем заявки авторов, которые заблокировали пользователя, и тех, кого заблокировал он сам
+	if currentUserID != nil {
+		query = query.
+			Where("user_id NOT IN (?)", blockedMe(*currentUserID)).
+			Where("user_id NOT IN (?)", blockedByMe(*currentUserID))
+	}
+
 	// Фильтры
 	if gameID := c.Query("game_id"); gameID != "" {
 		parsedGameID, err := uuid.Parse(gameID)
@@ -270,11 +322,15 @@ func GetAllApplications(c *fiber.Ctx) error {
 	result := query.Order("created_at DESC").Find(&applications)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch applications",
-		})
+		return apperror.Internal("fetch applications")
 	}
 
+	viewerID := uuid.Nil
+	if currentUserID != nil {
+		viewerID = *currentUserID
+	}
+	applyPrivacy(viewerID, applicationUsers(applications)...)
+
 	// Если пользователь авторизован, добавляем информацию об откликах
 	var applicationsWithResponse []ApplicationWithUserResponse
 	if currentUserID != nil {
@@ -337,104 +393,235 @@ func GetAllApplications(c *fiber.Ctx) error {
 func GetApplicationByID(c *fiber.Ctx) error {
 	appID := c.Params("id")
 	if appID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Application ID is required",
-		})
+		return apperror.Required("application_id")
 	}
 
 	parsedID, err := uuid.Parse(appID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid application ID format",
-		})
+		return apperror.InvalidID("application_id")
 	}
 
 	var application models.GameApplication
 	result := database.DB.
 		Preload("Game").
 		Preload("User").
+		Preload("Questions", orderQuestions).
 		First(&application, parsedID)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Application not found",
-		})
+		return apperror.ErrApplicationNotFound
+	}
+
+	// Скрытую модератором заявку видит только автор
+	if application.IsHidden {
+		currentUserID, _ := utils.GetUserIDFromContext(c)
+		if currentUserID != application.UserId {
+			return apperror.ErrApplicationNotFound
+		}
+	}
+
+	// Скрытая (unlisted/private) заявка по ID доступна по действующему share-коду,
+	// автору и тем, кто уже откликнулся или приглашен: отзыв кода закрывает доступ остальным
+	if application.Visibility != models.VisibilityPublic &&
+		!utils.VerifyShareCode(c.Query("share_code"), application.ID, application.ShareNonce) {
+		currentUserID, err := utils.GetUserIDFromContext(c)
+		if err != nil || !canViewPrivateApplication(&application, currentUserID) {
+			return apperror.ErrApplicationNotFound
+		}
 	}
 
+	currentUserID, _ := utils.GetUserIDFromContext(c)
+	applyPrivacy(currentUserID, &application.User)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"application": application,
 	})
 }
 
+// canViewPrivateApplication - автор или участник (отклик/приглашение) скрытой заявки
+func canViewPrivateApplication(application *models.GameApplication, userID uuid.UUID) bool {
+	if application.UserId == userID {
+		return true
+	}
+
+	var count int64
+	database.DB.Model(&models.ApplicationResponse{}).
+		Where("application_id = ? AND user_id = ?", application.ID, userID).
+		Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	database.DB.Model(&models.ApplicationInvitation{}).
+		Where("application_id = ? AND invitee_id = ?", application.ID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// GetApplicationByShareCode получает заявку по share-коду (в т.ч. скрытую)
+// GET /api/applications/shared/:code
+func GetApplicationByShareCode(c *fiber.Ctx) error {
+	code := c.Params("code")
+
+	appID, err := utils.ParseShareCode(code)
+	if err != nil {
+		return apperror.ErrShareCodeInvalid
+	}
+
+	var application models.GameApplication
+	if err := database.DB.Preload("Game").Preload("User").Preload("Questions", orderQuestions).First(&application, appID).Error; err != nil {
+		return apperror.ErrShareCodeInvalid
+	}
+
+	if !application.IsActive || application.IsHidden || !utils.VerifyShareCode(code, application.ID, application.ShareNonce) {
+		return apperror.ErrShareCodeInvalid
+	}
+
+	currentUserID, _ := utils.GetUserIDFromContext(c)
+	applyPrivacy(currentUserID, &application.User)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"application": application,
+		"share_code":  code,
+	})
+}
+
+// GetApplicationShareCode возвращает текущий share-код заявки (только создатель)
+// POST /api/applications/:id/share-code
+func GetApplicationShareCode(c *fiber.Ctx) error {
+	application, err := loadOwnApplication(c)
+	if err != nil {
+		return err
+	}
+
+	if application.ShareNonce == "" {
+		if err := rotateShareNonce(application); err != nil {
+			return apperror.Internal("generate share code")
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"share_code": utils.GenerateShareCode(application.ID, application.ShareNonce),
+	})
+}
+
+// RevokeApplicationShareCode отзывает все выданные ссылки и выдает новый код
+// DELETE /api/applications/:id/share-code
+func RevokeApplicationShareCode(c *fiber.Ctx) error {
+	application, err := loadOwnApplication(c)
+	if err != nil {
+		return err
+	}
+
+	if err := rotateShareNonce(application); err != nil {
+		return apperror.Internal("revoke share code")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":    "Share code revoked successfully",
+		"share_code": utils.GenerateShareCode(application.ID, application.ShareNonce),
+	})
+}
+
+func rotateShareNonce(application *models.GameApplication) error {
+	nonce, err := utils.NewShareNonce()
+	if err != nil {
+		return err
+	}
+	application.ShareNonce = nonce
+	return database.DB.Model(application).Update("share_nonce", nonce).Error
+}
+
+// loadOwnApplication загружает заявку из :id и проверяет, что текущий пользователь - ее автор.
+// Ошибки возвращаются как *apperror.Error и оформляются общим ErrorHandler
+func loadOwnApplication(c *fiber.Ctx) (*models.GameApplication, error) {
+	userID := c.Locals("userID")
+	if userID == nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	parsedUserID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		return nil, apperror.InvalidID("user_id")
+	}
+
+	parsedAppID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, apperror.InvalidID("application_id")
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, parsedAppID).Error; err != nil {
+		return nil, apperror.ErrApplicationNotFound
+	}
+
+	if application.UserId != parsedUserID {
+		return nil, apperror.ErrNotApplicationAuthor
+	}
+
+	return &application, nil
+}
+
 // UpdateApplication обновляет заявку (только создатель)
 func UpdateApplication(c *fiber.Ctx) error {
 	userID := c.Locals("userID")
 	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "User not authenticated",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	parsedUserID, err := uuid.Parse(userID.(string))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
 	appID := c.Params("id")
 	parsedAppID, err := uuid.Parse(appID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid application ID",
-		})
+		return apperror.InvalidID("application_id")
 	}
 
 	// Находим заявку
 	var application models.GameApplication
 	if err := database.DB.First(&application, parsedAppID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Application not found",
-		})
+		return apperror.ErrApplicationNotFound
 	}
 
 	// Проверяем владельца
 	if application.UserId != parsedUserID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "You don't have permission to update this application",
-		})
+		return apperror.ErrNotApplicationAuthor
 	}
 
 	// Парсим тело запроса
 	var req CreateGameApplicationRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	// Валидация
 	if req.Title == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Title is required",
-		})
+		return apperror.Required("title")
 	}
 	if req.Description == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Description is required",
-		})
+		return apperror.Required("description")
 	}
 	if req.MaxPlayers < req.MinPlayers {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Max players must be greater than or equal to min players",
-		})
+		return apperror.ErrInvalidPlayerLimits
 	}
 	if req.MinPlayers < 1 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Min players must be at least 1",
-		})
+		return apperror.ErrInvalidPlayerLimits
+	}
+
+	// Автофильтр проверяет только измененный текст
+	var filterResult contentfilter.Result
+	if req.Title != application.Title || req.Description != application.Description {
+		filterResult, err = checkContent(applicationText(req.Title, req.Description), recentApplicationTexts(parsedUserID, application.ID))
+		if err != nil {
+			return err
+		}
 	}
 
+	wasPublic := application.Visibility == models.VisibilityPublic
+
 	// Обновляем поля
 	application.Title = req.Title
 	application.Description = req.Description
@@ -444,16 +631,72 @@ func UpdateApplication(c *fiber.Ctx) error {
 	application.PrimeTimeEnd = req.PrimeTimeEnd
 	application.WithVoiceChat = req.WithVoiceChat
 	application.Platform = models.Platform(req.Platform)
+	if req.Visibility != "" {
+		visibility := models.Visibility(req.Visibility)
+		if !visibility.IsValid() {
+			return apperror.InvalidValue("visibility", "public", "unlisted", "private")
+		}
+		application.Visibility = visibility
+	}
+	if application.Visibility != models.VisibilityPublic && application.ShareNonce == "" {
+		nonce, err := utils.NewShareNonce()
+		if err != nil {
+			return apperror.Internal("generate share code")
+		}
+		application.ShareNonce = nonce
+	}
+
+	// Вопросы можно менять только пока на заявку никто не откликнулся,
+	// иначе старые ответы потеряют смысл
+	var questions []models.ApplicationQuestion
+	if req.Questions != nil {
+		var responsesCount int64
+		database.DB.Model(&models.ApplicationResponse{}).Where("application_id = ?", application.ID).Count(&responsesCount)
+		if responsesCount > 0 {
+			return apperror.ErrQuestionsLocked
+		}
+
+		questions, err = buildApplicationQuestions(*req.Questions)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Сохраняем изменения
-	if err := database.DB.Save(&application).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update application",
-		})
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&application).Error; err != nil {
+			return err
+		}
+		if req.Questions != nil {
+			if err := tx.Where("application_id = ?", application.ID).Delete(&models.ApplicationQuestion{}).Error; err != nil {
+				return err
+			}
+			for i := range questions {
+				questions[i].ApplicationID = application.ID
+			}
+			if len(questions) > 0 {
+				if err := tx.Create(&questions).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if wasPublic && application.Visibility != models.VisibilityPublic && !application.IsHidden {
+			if err := webhooks.DispatchUnlisted(tx, application.ID); err != nil {
+				return err
+			}
+		}
+		return webhooks.DispatchApplication(tx, models.WebhookApplicationUpdated, application.ID, "")
+	})
+	if err != nil {
+		return apperror.Internal("update application")
 	}
 
+	flagForReview(filterResult, models.ReportTargetApplication, application.ID, parsedUserID,
+		fiber.Map{"title": application.Title, "description": application.Description})
+
 	// Загружаем связанные данные
-	database.DB.Preload("Game").Preload("User").First(&application, application.ID)
+	database.DB.Preload("Game").Preload("User").Preload("Questions", orderQuestions).First(&application, application.ID)
+	applyPrivacy(parsedUserID, &application.User)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message":     "Application updated successfully",
@@ -465,47 +708,41 @@ func UpdateApplication(c *fiber.Ctx) error {
 func DeleteApplication(c *fiber.Ctx) error {
 	userID := c.Locals("userID")
 	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "User not authenticated",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	parsedUserID, err := uuid.Parse(userID.(string))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.InvalidID("user_id")
 	}
 
 	appID := c.Params("id")
 	parsedAppID, err := uuid.Parse(appID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid application ID",
-		})
+		return apperror.InvalidID("application_id")
 	}
 
 	var application models.GameApplication
 	if err := database.DB.First(&application, parsedAppID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Application not found",
-		})
+		return apperror.ErrApplicationNotFound
 	}
 
 	// Проверяем владельца
 	if application.UserId != parsedUserID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "You don't have permission to delete this application",
-		})
+		return apperror.ErrNotApplicationAuthor
 	}
 
 	// Мягкое удаление - помечаем как неактивную
 	// История откликов и чатов сохраняется, но заявка исчезает из списка
 	application.IsActive = false
-	if err := database.DB.Save(&application).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete application",
-		})
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&application).Error; err != nil {
+			return err
+		}
+		return webhooks.DispatchApplication(tx, models.WebhookApplicationClosed, application.ID, "deleted")
+	})
+	if err != nil {
+		return apperror.Internal("delete application")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
 	"github.com/gofiber/fiber/v2"
@@ -29,9 +30,7 @@ func GetAllGames(c *fiber.Ctx) error {
 	}
 
 	if err := countQuery.Count(&total).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to count games",
-		})
+		return apperror.Internal("count games")
 	}
 
 	var games []models.Game
@@ -46,9 +45,7 @@ func GetAllGames(c *fiber.Ctx) error {
 		Offset(offset).
 		Limit(limit).
 		Find(&games).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch games",
-		})
+		return apperror.Internal("fetch games")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -61,18 +58,14 @@ func GetAllGames(c *fiber.Ctx) error {
 func GetGameBySlug(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 	if slug == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Game slug is required",
-		})
+		return apperror.Required("slug")
 	}
 
 	game := models.Game{}
 	result := database.DB.Where("slug = ?", slug).First(&game)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Game not found",
-		})
+		return apperror.ErrGameNotFound
 	}
 
 	return c.Status(fiber.StatusOK).JSON(game)
@@ -81,25 +74,19 @@ func GetGameBySlug(c *fiber.Ctx) error {
 func GetGameById(c *fiber.Ctx) error {
 	gameID := c.Params("id")
 	if gameID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Game ID is required",
-		})
+		return apperror.Required("game_id")
 	}
 
 	parsedID, err := uuid.Parse(gameID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid game ID format",
-		})
+		return apperror.InvalidID("game_id")
 	}
 
 	game := models.Game{}
 	result := database.DB.Where("id = ?", parsedID).First(&game)
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Game not found",
-		})
+		return apperror.ErrGameNotFound
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/steam"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Ограничения полей игрового профиля
+const (
+	maxGameRoles       = 5
+	maxGameRoleLength  = 30
+	maxRankLength      = 50
+	maxInGameNickname  = 64
+	maxProfileURL      = 255
+	maxGameRating      = 100000
+	maxGameHours       = 1000000
+	gameProfileUserCap = 30
+)
+
+type gameProfileRequest struct {
+	GameID         *string   `json:"game_id"`
+	Rank           *string   `json:"rank"`
+	Rating         *int      `json:"rating"`
+	Roles          *[]string `json:"roles"`
+	Hours          *int      `json:"hours"`
+	InGameNickname *string   `json:"in_game_nickname"`
+	ProfileURL     *string   `json:"profile_url"`
+}
+
+// withGameProfiles - подгрузить игровые профили пользователя вместе с играми,
+// самые наигранные первыми
+func withGameProfiles(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("GameProfiles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("hours DESC NULLS LAST, created_at ASC")
+		}).
+		Preload("GameProfiles.Game")
+}
+
+// GetMyGameProfiles - мои игровые профили
+// GET /api/game-profiles
+func GetMyGameProfiles(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+	return sendGameProfiles(c, userID)
+}
+
+// GetUserGameProfiles - игровые профили пользователя
+// GET /api/users/:id/game-profiles
+func GetUserGameProfiles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+	return sendGameProfiles(c, userID)
+}
+
+func sendGameProfiles(c *fiber.Ctx, userID uuid.UUID) error {
+	var user models.User
+	if err := database.DB.Select("id").Scopes(withGameProfiles).First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.GameProfiles == nil {
+		user.GameProfiles = []models.UserGameProfile{}
+	}
+	return c.JSON(user.GameProfiles)
+}
+
+// CreateGameProfile - добавить профиль в игре. Для каждой игры профиль один
+// POST /api/game-profiles
+func CreateGameProfile(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req gameProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+	if req.GameID == nil {
+		return apperror.Required("game_id")
+	}
+	gameID, err := uuid.Parse(*req.GameID)
+	if err != nil {
+		return apperror.InvalidID("game_id")
+	}
+
+	var game models.Game
+	if err := database.DB.Where("id = ? AND is_active = ?", gameID, true).First(&game).Error; err != nil {
+		return apperror.ErrGameNotFound
+	}
+
+	var existing int64
+	database.DB.Model(&models.UserGameProfile{}).Where("user_id = ?", userID).Count(&existing)
+	if existing >= gameProfileUserCap {
+		return apperror.ErrGameProfileLimit.With("max", gameProfileUserCap)
+	}
+	var duplicate int64
+	database.DB.Model(&models.UserGameProfile{}).Where("user_id = ? AND game_id = ?", userID, gameID).Count(&duplicate)
+	if duplicate > 0 {
+		return apperror.ErrGameProfileExists
+	}
+
+	profile := models.UserGameProfile{UserID: userID, GameID: gameID}
+	if err := applyGameProfileRequest(&profile, &req); err != nil {
+		return err
+	}
+	if err := database.DB.Create(&profile).Error; err != nil {
+		if isUniqueViolation(err, "idx_user_game_profile") {
+			return apperror.ErrGameProfileExists
+		}
+		return apperror.Internal("create game profile")
+	}
+	// Если библиотека Steam уже загружена, часы подтверждаются сразу
+	if steamProfile := steam.CachedProfile(userID); steamProfile != nil {
+		if err := steam.ApplyVerifiedHours(database.DB, &profile, steamProfile); err != nil {
+			log.Printf("Failed to verify Steam hours for game profile %s: %v", profile.ID, err)
+		}
+	}
+
+	profile.Game = &game
+	return c.Status(fiber.StatusCreated).JSON(profile)
+}
+
+// UpdateGameProfile - изменить профиль. Переданные поля заменяются,
+// пустая строка или пустой список очищают поле
+// PATCH /api/game-profiles/:id
+func UpdateGameProfile(c *fiber.Ctx) error {
+	profile, err := ownGameProfile(c)
+	if err != nil {
+		return err
+	}
+
+	var req gameProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+	// Игру у профиля не меняем - для другой игры заводится отдельный профиль
+	req.GameID = nil
+	if err := applyGameProfileRequest(profile, &req); err != nil {
+		return err
+	}
+	if err := database.DB.Save(profile).Error; err != nil {
+		return apperror.Internal("update game profile")
+	}
+
+	database.DB.Preload("Game").First(profile, profile.ID)
+	return c.JSON(profile)
+}
+
+// DeleteGameProfile - удалить профиль
+// DELETE /api/game-profiles/:id
+func DeleteGameProfile(c *fiber.Ctx) error {
+	profile, err := ownGameProfile(c)
+	if err != nil {
+		return err
+	}
+
+	if err := database.DB.Delete(profile).Error; err != nil {
+		return apperror.Internal("delete game profile")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Game profile deleted",
+	})
+}
+
+// ownGameProfile - профиль из :id, если он принадлежит текущему пользователю
+func ownGameProfile(c *fiber.Ctx) (*models.UserGameProfile, error) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+	profileID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, apperror.InvalidID("profile_id")
+	}
+
+	var profile models.UserGameProfile
+	if err := database.DB.Where("id = ? AND user_id = ?", profileID, userID).First(&profile).Error; err != nil {
+		return nil, apperror.ErrGameProfileNotFound
+	}
+	return &profile, nil
+}
+
+// applyGameProfileRequest проверяет переданные поля и переносит их в профиль
+func applyGameProfileRequest(profile *models.UserGameProfile, req *gameProfileRequest) error {
+	var err error
+	if req.Rank != nil {
+		if profile.Rank, err = optionalText("rank", *req.Rank, maxRankLength); err != nil {
+			return err
+		}
+	}
+	if req.InGameNickname != nil {
+		if profile.InGameNickname, err = optionalText("in_game_nickname", *req.InGameNickname, maxInGameNickname); err != nil {
+			return err
+		}
+	}
+	if req.ProfileURL != nil {
+		if profile.ProfileURL, err = optionalText("profile_url", *req.ProfileURL, maxProfileURL); err != nil {
+			return err
+		}
+		if profile.ProfileURL != nil && !isWebURL(*profile.ProfileURL) {
+			return apperror.InvalidValue("profile_url")
+		}
+	}
+	if req.Rating != nil {
+		if *req.Rating < 0 || *req.Rating > maxGameRating {
+			return apperror.InvalidValue("rating")
+		}
+		profile.Rating = req.Rating
+	}
+	if req.Hours != nil {
+		if *req.Hours < 0 || *req.Hours > maxGameHours {
+			return apperror.InvalidValue("hours")
+		}
+		profile.Hours = req.Hours
+	}
+	if req.Roles != nil {
+		if profile.Roles, err = normalizeGameRoles(*req.Roles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optionalText - обрезанная строка или nil для пустой
+func optionalText(field, value string, maxLength int) (*string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if len([]rune(value)) > maxLength {
+		return nil, apperror.Field(apperror.ErrTooLong, field).With("max", maxLength)
+	}
+	return &value, nil
+}
+
+// normalizeGameRoles убирает пустые и повторяющиеся (без учета регистра) роли
+func normalizeGameRoles(roles []string) ([]string, error) {
+	result := make([]string, 0, len(roles))
+	seen := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if role == "" || seen[strings.ToLower(role)] {
+			continue
+		}
+		if len([]rune(role)) > maxGameRoleLength {
+			return nil, apperror.Field(apperror.ErrTooLong, "roles").With("max", maxGameRoleLength)
+		}
+		seen[strings.ToLower(role)] = true
+		result = append(result, role)
+	}
+	if len(result) > maxGameRoles {
+		return nil, apperror.ErrTooManyGameRoles.With("max", maxGameRoles)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func isWebURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// applyGameProfileFilters - фильтры откликов по игровому профилю откликнувшегося
+// в игре заявки: ?profile_rating_min=4000&profile_rating_max=5000&profile_hours_min=1000&profile_role=pos 4.
+// ?has_profile=true оставляет только игроков, заполнивших профиль этой игры
+func applyGameProfileFilters(c *fiber.Ctx, query *gorm.DB, gameID uuid.UUID) (*gorm.DB, error) {
+	profiles := database.DB.Model(&models.UserGameProfile{}).Select("user_id").Where("game_id = ?", gameID)
+	filtered := c.Query("has_profile") == "true"
+
+	ranges := []struct {
+		param  string
+		filter string
+	}{
+		{"profile_rating_min", "rating >= ?"},
+		{"profile_rating_max", "rating <= ?"},
+		{"profile_hours_min", "hours >= ?"},
+		{"profile_hours_max", "hours <= ?"},
+	}
+	for _, r := range ranges {
+		value := c.Query(r.param)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, apperror.ErrFilterInvalidNumber.With("filter", r.param)
+		}
+		profiles = profiles.Where(r.filter, number)
+		filtered = true
+	}
+
+	// Роли сравниваются без учета регистра: "Pos 4" и "pos 4" - одно и то же
+	if role := strings.TrimSpace(c.Query("profile_role")); role != "" {
+		profiles = profiles.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(roles) AS role WHERE lower(role) = lower(?))", role)
+		filtered = true
+	}
+
+	if filtered {
+		query = query.Where("user_id IN (?)", profiles)
+	}
+	return query, nil
+}
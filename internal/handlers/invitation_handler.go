@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/webhooks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateInvitation - пригласить игрока в свою заявку
+// POST /api/applications/:id/invitations
+func CreateInvitation(c *fiber.Ctx) error {
+	appUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("application_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		UserID  string  `json:"user_id"`
+		Message *string `json:"message"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	inviteeID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return apperror.InvalidID("invitee_id")
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, appUUID).Error; err != nil {
+		return apperror.ErrApplicationNotFound
+	}
+
+	// Приглашать может только автор заявки
+	if application.UserId != userID {
+		return apperror.ErrNotApplicationAuthor
+	}
+
+	if !application.IsActive {
+		return apperror.ErrApplicationInactive
+	}
+
+	if application.IsFull {
+		return apperror.ErrApplicationFull
+	}
+
+	if inviteeID == userID {
+		return apperror.ErrSelfAction.With("action", "invite")
+	}
+
+	var invitee models.User
+	if err := database.DB.First(&invitee, inviteeID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	if isBlockedEitherWay(userID, inviteeID) {
+		return apperror.ErrCannotInvite
+	}
+
+	// Игрок уже в составе или ждет решения по своему отклику
+	var activeResponses int64
+	database.DB.Model(&models.ApplicationResponse{}).
+		Where("application_id = ? AND user_id = ? AND status IN ?", appUUID, inviteeID,
+			[]models.Status{models.StatusPending, models.StatusAccepted}).
+		Count(&activeResponses)
+	if activeResponses > 0 {
+		return apperror.ErrAlreadyResponded
+	}
+
+	var pendingInvitations int64
+	database.DB.Model(&models.ApplicationInvitation{}).
+		Where("application_id = ? AND invitee_id = ? AND status = ?", appUUID, inviteeID, models.InvitationPending).
+		Count(&pendingInvitations)
+	if pendingInvitations > 0 {
+		return apperror.ErrAlreadyInvited
+	}
+
+	invitation := models.ApplicationInvitation{
+		ApplicationID: appUUID,
+		InviterID:     userID,
+		InviteeID:     inviteeID,
+		Message:       req.Message,
+		Status:        models.InvitationPending,
+	}
+	if err := database.DB.Create(&invitation).Error; err != nil {
+		return apperror.Internal("create invitation")
+	}
+
+	database.DB.Preload("Invitee").Preload("Application").First(&invitation, invitation.ID)
+	applyPrivacy(userID, invitation.Invitee)
+
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+// GetApplicationInvitations - приглашения, отправленные по заявке (только для автора)
+// GET /api/applications/:id/invitations
+func GetApplicationInvitations(c *fiber.Ctx) error {
+	appUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("application_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, appUUID).Error; err != nil {
+		return apperror.ErrApplicationNotFound
+	}
+
+	if application.UserId != userID {
+		return apperror.ErrNotApplicationAuthor
+	}
+
+	var invitations []models.ApplicationInvitation
+	if err := database.DB.
+		Preload("Invitee").
+		Where("application_id = ?", appUUID).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return apperror.Internal("fetch invitations")
+	}
+
+	invitees := make([]*models.User, 0, len(invitations))
+	for i := range invitations {
+		invitees = append(invitees, invitations[i].Invitee)
+	}
+	applyPrivacy(userID, invitees...)
+
+	return c.JSON(invitations)
+}
+
+// GetMyInvitations - приглашения, полученные текущим пользователем
+// GET /api/invitations/my?status=pending
+func GetMyInvitations(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	query := database.DB.
+		Preload("Inviter").
+		Preload("Application").
+		Preload("Application.Game").
+		Where("invitee_id = ?", userID)
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var invitations []models.ApplicationInvitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return apperror.Internal("fetch invitations")
+	}
+
+	inviters := make([]*models.User, 0, len(invitations))
+	for i := range invitations {
+		inviters = append(inviters, invitations[i].Inviter)
+	}
+	applyPrivacy(userID, inviters...)
+
+	return c.JSON(invitations)
+}
+
+// AcceptInvitation - принять приглашение: игрок сразу попадает в состав
+// POST /api/invitations/:id/accept
+func AcceptInvitation(c *fiber.Ctx) error {
+	invitation, userID, err := loadInvitationForInvitee(c)
+	if err != nil {
+		return err
+	}
+
+	if isBlockedEitherWay(invitation.InviterID, userID) {
+		return apperror.ErrCannotAcceptInvitation
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Занимаем место в заявке (с учетом MaxPlayers/IsFull)
+	application, err := occupyApplicationSlot(tx, invitation.ApplicationID)
+	if err != nil {
+		tx.Rollback()
+		return responseStatusError(err)
+	}
+
+	// 2. Создаем принятый отклик (или переиспользуем прежний отклик игрока)
+	now := time.Now()
+	var response models.ApplicationResponse
+	err = tx.Where("application_id = ? AND user_id = ?", application.ID, userID).First(&response).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		response = models.ApplicationResponse{
+			ApplicationID: application.ID,
+			UserID:        userID,
+			Status:        models.StatusAccepted,
+		}
+		err = tx.Create(&response).Error
+	case err == nil && response.Status == models.StatusAccepted:
+		tx.Rollback()
+		return apperror.ErrAlreadyMember
+	case err == nil:
+		response.Status = models.StatusAccepted
+		response.StatusChangedAt = nil
+		err = tx.Save(&response).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return apperror.Internal("create response")
+	}
+
+	// 3. Диалог между автором и игроком
+	conversation, err := findOrCreateConversation(tx, response.ID, application.UserId, userID)
+	if err != nil {
+		tx.Rollback()
+		return apperror.Internal("create conversation")
+	}
+
+	// Текст приглашения становится первым сообщением автора
+	if invitation.Message != nil && *invitation.Message != "" {
+		message := models.Message{
+			ConversationID: conversation.ID,
+			SenderID:       invitation.InviterID,
+			Content:        *invitation.Message,
+			CreatedAt:      invitation.CreatedAt,
+		}
+		if err := tx.Create(&message).Error; err != nil {
+			tx.Rollback()
+			return apperror.Internal("create message")
+		}
+	}
+
+	// 4. Закрываем приглашение
+	invitation.Status = models.InvitationAccepted
+	invitation.ResponseID = &response.ID
+	invitation.RespondedAt = &now
+	if err := tx.Save(invitation).Error; err != nil {
+		tx.Rollback()
+		return apperror.Internal("update invitation")
+	}
+
+	if err := webhooks.DispatchResponse(tx, models.WebhookResponseAccepted, response.ID); err != nil {
+		tx.Rollback()
+		return apperror.Internal("dispatch webhooks")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperror.Internal("commit transaction")
+	}
+
+	database.DB.Preload("Application").Preload("Application.Game").Preload("Conversation").First(&response, response.ID)
+	response.HideAuthorFields()
+
+	return c.JSON(fiber.Map{
+		"invitation": invitation,
+		"response":   response,
+	})
+}
+
+// DeclineInvitation - отклонить приглашение
+// POST /api/invitations/:id/decline
+func DeclineInvitation(c *fiber.Ctx) error {
+	invitation, _, err := loadInvitationForInvitee(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationDeclined
+	invitation.RespondedAt = &now
+	if err := database.DB.Save(invitation).Error; err != nil {
+		return apperror.Internal("update invitation")
+	}
+
+	return c.JSON(invitation)
+}
+
+// CancelInvitation - автор отзывает еще не принятое приглашение
+// DELETE /api/invitations/:id
+func CancelInvitation(c *fiber.Ctx) error {
+	invUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("invitation_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var invitation models.ApplicationInvitation
+	if err := database.DB.First(&invitation, invUUID).Error; err != nil {
+		return apperror.ErrInvitationNotFound
+	}
+
+	if invitation.InviterID != userID {
+		return apperror.ErrNotInviter
+	}
+
+	if invitation.Status != models.InvitationPending {
+		return apperror.ErrInvitationNotPending
+	}
+
+	invitation.Status = models.InvitationCancelled
+	if err := database.DB.Save(&invitation).Error; err != nil {
+		return apperror.Internal("cancel invitation")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Invitation cancelled successfully",
+	})
+}
+
+// loadInvitationForInvitee загружает ожидающее приглашение и проверяет, что оно адресовано текущему пользователю.
+// Ошибки возвращаются как *apperror.Error и оформляются общим ErrorHandler
+func loadInvitationForInvitee(c *fiber.Ctx) (*models.ApplicationInvitation, uuid.UUID, error) {
+	invUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, uuid.Nil, apperror.InvalidID("invitation_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return nil, uuid.Nil, apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, uuid.Nil, apperror.ErrUnauthorized
+	}
+
+	var invitation models.ApplicationInvitation
+	if err := database.DB.First(&invitation, invUUID).Error; err != nil {
+		return nil, uuid.Nil, apperror.ErrInvitationNotFound
+	}
+
+	if invitation.InviteeID != userID {
+		return nil, uuid.Nil, apperror.ErrNotInvitee
+	}
+
+	if invitation.Status != models.InvitationPending {
+		return nil, uuid.Nil, apperror.ErrInvitationNotPending
+	}
+
+	return &invitation, userID, nil
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/services/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ServeMedia - раздача загруженных файлов из хранилища. Ключи меняются вместе
+// с содержимым, поэтому открытые файлы кешируются браузером и CDN без перепроверки
+// GET /media/*
+func ServeMedia(c *fiber.Ctx) error {
+	key := c.Params("*")
+	if !storage.ValidKey(key) {
+		return apperror.ErrMediaNotFound
+	}
+	// Закрытые файлы - только по временной ссылке из storage.SignedURL.
+	// Подпись у открытого файла тоже проверяем, чтобы истекшая ссылка не работала
+	signature := c.Query("signature")
+	signed := signature != "" || strings.HasPrefix(key, storage.PrivatePrefix)
+	if signed {
+		if err := storage.VerifySignature(key, c.Query("expires"), signature); err != nil {
+			return apperror.ErrMediaLinkInvalid
+		}
+	}
+
+	body, object, err := storage.Default.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return apperror.ErrMediaNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to read stored file %s: %v", key, err)
+		return apperror.Internal("read file")
+	}
+
+	etag := fmt.Sprintf(`"%x-%x"`, object.ModTime.UnixNano(), object.Size)
+	if signed {
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+	} else {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	}
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, object.ModTime.UTC().Format(http.TimeFormat))
+	// Пользовательские файлы не должны исполняться как HTML/скрипты
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderContentSecurityPolicy, "default-src 'none'; sandbox")
+
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		body.Close()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, object.ContentType)
+	return c.SendStream(body, int(object.Size))
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"strings"
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/notifications"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -16,7 +19,7 @@ import (
 func GetUserConversations(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	var conversations []models.Conversation
@@ -35,13 +38,13 @@ func GetUserConversations(c *fiber.Ctx) error {
 		}).
 		Where("participant1_id = ? OR participant2_id = ?", userID, userID).
 		Where("is_archived = ?", false).
+		// Hide conversations with users the current user has blocked
+		Where("participant1_id NOT IN (?) AND participant2_id NOT IN (?)", blockedByMe(userID), blockedByMe(userID)).
 		Order("last_message_at DESC NULLS LAST").
 		Find(&conversations).Error
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch conversations",
-		})
+		return apperror.Internal("fetch conversations")
 	}
 
 	// Calculate unread count for each conversation
@@ -80,12 +83,12 @@ func GetUserConversations(c *fiber.Ctx) error {
 func GetConversationByID(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	conversationID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid conversation ID"})
+		return apperror.InvalidID("conversation_id")
 	}
 
 	var conversation models.Conversation
@@ -97,16 +100,24 @@ func GetConversationByID(c *fiber.Ctx) error {
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Conversation not found"})
+			return apperror.ErrConversationNotFound
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch conversation"})
+		return apperror.Internal("fetch conversation")
 	}
 
 	// Security: Check if user is a participant
 	if conversation.Participant1ID != userID && conversation.Participant2ID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return apperror.ErrAccessDenied
+	}
+
+	// Private author notes are visible to the application author only
+	if conversation.Response != nil && conversation.Response.UserID == userID {
+		conversation.Response.HideAuthorFields()
 	}
 
+	// The other participant's contacts follow their privacy settings
+	applyPrivacy(userID, conversation.Participant1, conversation.Participant2)
+
 	return c.JSON(conversation)
 }
 
@@ -115,12 +126,12 @@ func GetConversationByID(c *fiber.Ctx) error {
 func GetConversationMessages(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	conversationID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid conversation ID"})
+		return apperror.InvalidID("conversation_id")
 	}
 
 	// Verify user is a participant
@@ -130,13 +141,13 @@ func GetConversationMessages(c *fiber.Ctx) error {
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Conversation not found"})
+			return apperror.ErrConversationNotFound
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify conversation"})
+		return apperror.Internal("verify conversation")
 	}
 
 	if conversation.Participant1ID != userID && conversation.Participant2ID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return apperror.ErrAccessDenied
 	}
 
 	// Pagination parameters
@@ -168,9 +179,7 @@ func GetConversationMessages(c *fiber.Ctx) error {
 		Find(&messages).Error
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch messages",
-		})
+		return apperror.Internal("fetch messages")
 	}
 
 	return c.JSON(fiber.Map{
@@ -184,17 +193,84 @@ func GetConversationMessages(c *fiber.Ctx) error {
 	})
 }
 
+// SendMessage sends a message to a conversation
+// Blocked users cannot write to each other even if they share a conversation
+func SendMessage(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("conversation_id")
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return apperror.Required("content")
+	}
+
+	var conversation models.Conversation
+	if err := database.DB.First(&conversation, "id = ?", conversationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperror.ErrConversationNotFound
+		}
+		return apperror.Internal("fetch conversation")
+	}
+
+	if conversation.Participant1ID != userID && conversation.Participant2ID != userID {
+		return apperror.ErrAccessDenied
+	}
+
+	otherUserID := conversation.Participant1ID
+	if otherUserID == userID {
+		otherUserID = conversation.Participant2ID
+	}
+	if isBlockedEitherWay(userID, otherUserID) {
+		return apperror.ErrCannotMessageUser
+	}
+
+	message := models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		Content:        content,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&conversation).Update("last_message_at", message.CreatedAt).Error; err != nil {
+			return err
+		}
+		return notifications.NotifyNewMessage(tx, otherUserID, userID, conversation.ID, content)
+	})
+	if err != nil {
+		return apperror.Internal("send message")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(message)
+}
+
 // MarkMessagesAsRead marks all messages in a conversation as read
 // Optimized: Single UPDATE query instead of updating each message individually
 func MarkMessagesAsRead(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	conversationID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid conversation ID"})
+		return apperror.InvalidID("conversation_id")
 	}
 
 	// Verify user is a participant
@@ -203,11 +279,11 @@ func MarkMessagesAsRead(c *fiber.Ctx) error {
 		First(&conversation, "id = ?", conversationID).Error
 
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Conversation not found"})
+		return apperror.ErrConversationNotFound
 	}
 
 	if conversation.Participant1ID != userID && conversation.Participant2ID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Access denied"})
+		return apperror.ErrAccessDenied
 	}
 
 	// Bulk update: Mark all unread messages from the other user as read
@@ -220,11 +296,14 @@ func MarkMessagesAsRead(c *fiber.Ctx) error {
 		})
 
 	if result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to mark messages as read",
-		})
+		return apperror.Internal("mark messages as read")
 	}
 
+	// Уведомление о новых сообщениях в этом диалоге больше не актуально
+	database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND type = ? AND entity_id = ? AND read_at IS NULL", userID, models.NotificationNewMessage, conversationID).
+		Update("read_at", now)
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"marked_count": result.RowsAffected,
@@ -235,7 +314,7 @@ func MarkMessagesAsRead(c *fiber.Ctx) error {
 func GetUnreadCount(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	// Get all conversation IDs where user is a participant
@@ -243,6 +322,7 @@ func GetUnreadCount(c *fiber.Ctx) error {
 	database.DB.Model(&models.Conversation{}).
 		Select("id").
 		Where("(participant1_id = ? OR participant2_id = ?) AND is_archived = ?", userID, userID, false).
+		Where("participant1_id NOT IN (?) AND participant2_id NOT IN (?)", blockedByMe(userID), blockedByMe(userID)).
 		Pluck("id", &conversationIDs)
 
 	// Count unread messages across all conversations
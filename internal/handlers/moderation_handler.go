@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/webhooks"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// suspendUser выставляет временную (until) или бессрочную (bannedAt) блокировку.
+// Передача обоих nil снимает блокировку
+func suspendUser(tx *gorm.DB, userID uuid.UUID, until, bannedAt *time.Time, reason *string) error {
+	return tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"suspended_until":   until,
+			"banned_at":         bannedAt,
+			"suspension_reason": reason,
+		}).Error
+}
+
+// GetModerationReports - очередь жалоб для модераторов
+// GET /api/moderation/reports?status=open&target_type=message&page=1&limit=20
+func GetModerationReports(c *fiber.Ctx) error {
+	query := database.DB.Model(&models.Report{}).
+		Where("status = ?", c.Query("status", string(models.ReportOpen)))
+
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if reason := c.Query("reason_code"); reason != "" {
+		query = query.Where("reason_code = ?", reason)
+	}
+
+	var reports []models.Report
+	page, err := paginate(c, query, &reports, pageLimits{Default: 20, Max: 100}, func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload("Reporter", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "nickname", "avatar_url")
+			}).
+			Order("created_at ASC") // Старые жалобы разбираются первыми
+	})
+	if err != nil {
+		return apperror.Internal("fetch reports")
+	}
+
+	return c.JSON(fiber.Map{
+		"reports": reports,
+		"count":   len(reports),
+		"total":   page.Total,
+	})
+}
+
+// GetModerationReport - жалоба с историей действий по ней
+// GET /api/moderation/reports/:id
+func GetModerationReport(c *fiber.Ctx) error {
+	reportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("report_id")
+	}
+
+	var report models.Report
+	if err := database.DB.
+		Preload("Reporter", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname", "avatar_url")
+		}).
+		Preload("Actions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Actions.Moderator", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname")
+		}).
+		First(&report, reportID).Error; err != nil {
+		return apperror.ErrReportNotFound
+	}
+
+	// Прошлые нарушения владельца контента помогают принять решение
+	var previousActions int64
+	database.DB.Model(&models.ModerationAction{}).
+		Where("target_user_id = ? AND action <> ?", report.TargetUserID, models.ActionDismiss).
+		Count(&previousActions)
+
+	return c.JSON(fiber.Map{
+		"report":              report,
+		"target_user_actions": previousActions,
+	})
+}
+
+// TakeModerationAction - применить решение по жалобе
+// POST /api/moderation/reports/:id/actions
+func TakeModerationAction(c *fiber.Ctx) error {
+	moderatorID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	reportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("report_id")
+	}
+
+	var req struct {
+		Action        string  `json:"action"`
+		Note          *string `json:"note"`
+		DurationHours int     `json:"duration_hours"` // Для suspend
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	action := models.ModerationActionType(req.Action)
+	if !action.IsValid() {
+		return apperror.InvalidValue("action", "hide_application", "delete_message", "warn", "suspend", "ban", "dismiss")
+	}
+
+	var report models.Report
+	if err := database.DB.First(&report, reportID).Error; err != nil {
+		return apperror.ErrReportNotFound
+	}
+
+	if report.Status != models.ReportOpen {
+		return apperror.ErrReportResolved
+	}
+
+	if action == models.ActionHideApplication && report.TargetType != models.ReportTargetApplication ||
+		action == models.ActionDeleteMessage && report.TargetType != models.ReportTargetMessage {
+		return apperror.ErrActionTargetMismatch
+	}
+
+	if action == models.ActionSuspend && req.DurationHours <= 0 {
+		return apperror.Required("duration_hours")
+	}
+
+	// По жалобе действуют те же ограничения, что и при прямой блокировке
+	if action == models.ActionSuspend || action == models.ActionBan {
+		if _, err := loadSuspensionTarget(moderatorID, report.TargetUserID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	entry := models.ModerationAction{
+		ReportID:     &report.ID,
+		ModeratorID:  moderatorID,
+		Action:       action,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetID,
+		TargetUserID: report.TargetUserID,
+		Note:         req.Note,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		switch action {
+		case models.ActionHideApplication:
+			// Событие уходит до скрытия, пока заявка еще видна подписчикам публичной ленты
+			if err := webhooks.DispatchApplication(tx, models.WebhookApplicationClosed, report.TargetID, "hidden"); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.GameApplication{}).
+				Where("id = ?", report.TargetID).
+				Update("is_hidden", true).Error; err != nil {
+				return err
+			}
+		case models.ActionDeleteMessage:
+			// Содержимое сохраняется в target_snapshot жалобы
+			if err := tx.Delete(&models.Message{}, "id = ?", report.TargetID).Error; err != nil {
+				return err
+			}
+		case models.ActionSuspend:
+			until := now.Add(time.Duration(req.DurationHours) * time.Hour)
+			entry.ExpiresAt = &until
+			if err := suspendUser(tx, report.TargetUserID, &until, nil, req.Note); err != nil {
+				return err
+			}
+		case models.ActionBan:
+			if err := suspendUser(tx, report.TargetUserID, nil, &now, req.Note); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+
+		// Отклонение закрывает только эту жалобу, действие - все открытые жалобы на тот же объект
+		resolved := tx.Model(&models.Report{}).Where("id = ?", report.ID)
+		status := models.ReportDismissed
+		if action != models.ActionDismiss {
+			status = models.ReportActioned
+			resolved = tx.Model(&models.Report{}).
+				Where("target_type = ? AND target_id = ? AND status = ?", report.TargetType, report.TargetID, models.ReportOpen)
+		}
+		return resolved.Updates(map[string]interface{}{
+			"status":         status,
+			"resolved_by_id": moderatorID,
+			"resolved_at":    now,
+		}).Error
+	})
+	if err != nil {
+		return apperror.Internal("apply moderation action")
+	}
+
+	database.DB.Preload("Actions").First(&report, report.ID)
+
+	return c.JSON(report)
+}
+
+// GetModerationActions - журнал действий модераторов
+// GET /api/moderation/actions?target_user_id=...&target_type=...&target_id=...
+func GetModerationActions(c *fiber.Ctx) error {
+	query := database.DB.Model(&models.ModerationAction{})
+	if targetUserID := c.Query("target_user_id"); targetUserID != "" {
+		query = query.Where("target_user_id = ?", targetUserID)
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if moderatorID := c.Query("moderator_id"); moderatorID != "" {
+		query = query.Where("moderator_id = ?", moderatorID)
+	}
+
+	var actions []models.ModerationAction
+	page, err := paginate(c, query, &actions, pageLimits{Default: 50, Max: 100}, func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload("Moderator", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "nickname")
+			}).
+			Order("created_at DESC")
+	})
+	if err != nil {
+		return apperror.Internal("fetch moderation actions")
+	}
+
+	return c.JSON(fiber.Map{
+		"actions": actions,
+		"count":   len(actions),
+		"total":   page.Total,
+	})
+}
+
+// SuspendUser - заблокировать пользователя вне жалобы
+// POST /api/moderation/users/:id/suspension
+func SuspendUser(c *fiber.Ctx) error {
+	moderatorID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	targetUserID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	var req struct {
+		Permanent     bool    `json:"permanent"`
+		DurationHours int     `json:"duration_hours"`
+		Reason        *string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if !req.Permanent && req.DurationHours <= 0 {
+		return apperror.Required("duration_hours")
+	}
+
+	if targetUserID == moderatorID {
+		return apperror.ErrSelfAction.With("action", "suspend")
+	}
+
+	target, err := loadSuspensionTarget(moderatorID, targetUserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := models.ModerationAction{
+		ModeratorID:  moderatorID,
+		Action:       models.ActionSuspend,
+		TargetType:   models.ReportTargetUser,
+		TargetID:     target.ID,
+		TargetUserID: target.ID,
+		Note:         req.Reason,
+	}
+
+	var until, bannedAt *time.Time
+	if req.Permanent {
+		entry.Action = models.ActionBan
+		bannedAt = &now
+	} else {
+		expires := now.Add(time.Duration(req.DurationHours) * time.Hour)
+		until = &expires
+		entry.ExpiresAt = until
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := suspendUser(tx, target.ID, until, bannedAt, req.Reason); err != nil {
+			return err
+		}
+		return tx.Create(&entry).Error
+	})
+	if err != nil {
+		return apperror.Internal("suspend user")
+	}
+
+	return c.JSON(entry)
+}
+
+// UnsuspendUser - снять блокировку досрочно
+// DELETE /api/moderation/users/:id/suspension
+func UnsuspendUser(c *fiber.Ctx) error {
+	moderatorID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	targetUserID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	target, err := loadSuspensionTarget(moderatorID, targetUserID)
+	if err != nil {
+		return err
+	}
+
+	if !target.IsSuspended(time.Now()) {
+		return apperror.ErrNotSuspended
+	}
+
+	entry := models.ModerationAction{
+		ModeratorID:  moderatorID,
+		Action:       models.ActionUnsuspend,
+		TargetType:   models.ReportTargetUser,
+		TargetID:     target.ID,
+		TargetUserID: target.ID,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := suspendUser(tx, target.ID, nil, nil, nil); err != nil {
+			return err
+		}
+		return tx.Create(&entry).Error
+	})
+	if err != nil {
+		return apperror.Internal("lift suspension")
+	}
+
+	return c.JSON(entry)
+}
+
+// loadSuspensionTarget загружает пользователя для блокировки.
+// Модератор не может блокировать модераторов и админов - это делает только админ.
+// Ошибки возвращаются как *apperror.Error
+func loadSuspensionTarget(moderatorID, targetUserID uuid.UUID) (*models.User, error) {
+	var target models.User
+	if err := database.DB.
+		Select("id", "role", "suspended_until", "banned_at").
+		First(&target, targetUserID).Error; err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	if target.Role.CanModerate() {
+		var moderator models.User
+		database.DB.Select("id", "role").First(&moderator, moderatorID)
+		if !moderator.Role.CanSuspend(target.Role) {
+			return nil, apperror.ErrCannotSuspendStaff
+		}
+	}
+
+	return &target, nil
+}
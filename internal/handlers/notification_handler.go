@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetNotifications - уведомления текущего пользователя, новые сверху
+// GET /api/notifications?page=1&limit=20&unread=true
+func GetNotifications(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	query := database.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.QueryBool("unread", false) {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	page, err := paginate(c, query, &notifications, pageLimits{Default: 20, Max: 50}, func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload("Actor", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "nickname", "avatar_url")
+			}).
+			Preload("Application", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "title", "game_id")
+			}).
+			Order("created_at DESC")
+	})
+	if err != nil {
+		return apperror.Internal("fetch notifications")
+	}
+
+	return c.JSON(fiber.Map{
+		"notifications": notifications,
+		"count":         len(notifications),
+		"total":         page.Total,
+		"page":          page.Page,
+		"limit":         page.Limit,
+	})
+}
+
+// GetUnreadNotificationsCount - счетчик непрочитанных для бейджа
+// GET /api/notifications/unread-count
+func GetUnreadNotificationsCount(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return apperror.Internal("count notifications")
+	}
+
+	return c.JSON(fiber.Map{
+		"unread_count": count,
+	})
+}
+
+// MarkNotificationRead - отметить уведомление прочитанным
+// POST /api/notifications/:id/read
+func MarkNotificationRead(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("notification_id")
+	}
+
+	var notification models.Notification
+	if err := database.DB.Where("id = ? AND user_id = ?", notificationID, userID).First(&notification).Error; err != nil {
+		return apperror.ErrNotificationNotFound
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := database.DB.Model(&notification).Update("read_at", now).Error; err != nil {
+			return apperror.Internal("mark notification as read")
+		}
+	}
+
+	return c.JSON(notification)
+}
+
+// MarkAllNotificationsRead - отметить прочитанными все уведомления
+// POST /api/notifications/read-all
+func MarkAllNotificationsRead(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return apperror.Internal("mark notifications as read")
+	}
+
+	return c.JSON(fiber.Map{
+		"success":      true,
+		"marked_count": result.RowsAffected,
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// pageLimits - размер страницы по умолчанию и максимальный
+type pageLimits struct {
+	Default int
+	Max     int
+}
+
+// pageInfo - выбранная страница и общее число записей
+type pageInfo struct {
+	Page  int
+	Limit int
+	Total int64
+}
+
+// paginate считает записи query и выбирает в dest страницу из ?page=1&limit=20.
+// Сортировку и Preload добавляет fetch - подсчету они не нужны
+func paginate(c *fiber.Ctx, query *gorm.DB, dest any, limits pageLimits, fetch func(*gorm.DB) *gorm.DB) (pageInfo, error) {
+	info := pageInfo{
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", limits.Default),
+	}
+	if info.Page < 1 {
+		info.Page = 1
+	}
+	if info.Limit < 1 || info.Limit > limits.Max {
+		info.Limit = limits.Default
+	}
+
+	// Один и тот же запрос используется для подсчета и выборки
+	query = query.Session(&gorm.Session{})
+	if err := query.Count(&info.Total).Error; err != nil {
+		return info, err
+	}
+
+	err := fetch(query).
+		Offset((info.Page - 1) * info.Limit).
+		Limit(info.Limit).
+		Find(dest).Error
+	return info, err
+}
@@ -7,12 +7,14 @@ import (
 	"log"
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
 	"github.com/duker221/teamly/internal/services/email"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
@@ -27,15 +29,11 @@ func ForgotPassword(c *fiber.Ctx) error {
 
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("[ForgotPassword] Body parse error: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email is required",
-		})
+		return apperror.Required("email")
 	}
 
 	// Всегда возвращаем успех для защиты от email enumeration
@@ -61,9 +59,7 @@ func ForgotPassword(c *fiber.Ctx) error {
 	rawToken, err := generateSecureToken(tokenLength)
 	if err != nil {
 		log.Printf("[ForgotPassword] Failed to generate token: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate reset token",
-		})
+		return apperror.Internal("generate reset token")
 	}
 
 	// Хешируем токен для хранения в БД
@@ -77,17 +73,17 @@ func ForgotPassword(c *fiber.Ctx) error {
 		ExpiresAt: time.Now().Add(tokenExpiresIn),
 	}
 
-	if err := database.DB.Create(&resetToken).Error; err != nil {
+	// Токен и письмо с ним сохраняются атомарно - письмо доставит outbox-воркер с повторами
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&resetToken).Error; err != nil {
+			return err
+		}
+		// Отправляем email с raw токеном (не хешем)
+		return email.QueuePasswordResetEmail(tx, user.Email, user.Locale, rawToken)
+	})
+	if err != nil {
 		log.Printf("[ForgotPassword] Failed to save token: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create reset token",
-		})
-	}
-
-	// Отправляем email с raw токеном (не хешем)
-	if err := email.SendPasswordResetEmail(user.Email, rawToken); err != nil {
-		log.Printf("[ForgotPassword] Failed to send email: %v", err)
-		// Не возвращаем ошибку клиенту для защиты от enumeration
+		return apperror.Internal("create reset token")
 	}
 
 	log.Printf("[ForgotPassword] Reset token created for user: %s", user.ID)
@@ -101,27 +97,19 @@ func ResetPassword(c *fiber.Ctx) error {
 
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("[ResetPassword] Body parse error: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	if req.Token == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Token is required",
-		})
+		return apperror.Required("token")
 	}
 
 	if req.NewPassword == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "New password is required",
-		})
+		return apperror.Required("password")
 	}
 
 	if len(req.NewPassword) < 6 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Password must be at least 6 characters",
-		})
+		return apperror.Field(apperror.ErrTooShort, "password").With("min", 6)
 	}
 
 	// Хешируем переданный токен для поиска в БД
@@ -132,26 +120,20 @@ func ResetPassword(c *fiber.Ctx) error {
 	result := database.DB.Where("token = ?", tokenHash).First(&resetToken)
 	if result.Error != nil {
 		log.Printf("[ResetPassword] Token not found")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid or expired token",
-		})
+		return apperror.ErrInvalidToken
 	}
 
 	// Проверяем валидность токена
 	if !resetToken.IsValid() {
 		log.Printf("[ResetPassword] Token is invalid (expired or used)")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid or expired token",
-		})
+		return apperror.ErrInvalidToken
 	}
 
 	// Хешируем новый пароль
 	hashedPassword, err := utils.HashPassword(req.NewPassword)
 	if err != nil {
 		log.Printf("[ResetPassword] Failed to hash password: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update password",
-		})
+		return apperror.Internal("update password")
 	}
 
 	// Обновляем пароль пользователя
@@ -159,9 +141,7 @@ func ResetPassword(c *fiber.Ctx) error {
 		Where("id = ?", resetToken.UserID).
 		Update("password_hash", hashedPassword).Error; err != nil {
 		log.Printf("[ResetPassword] Failed to update password: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update password",
-		})
+		return apperror.Internal("update password")
 	}
 
 	// Помечаем токен как использованный
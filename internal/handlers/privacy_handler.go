@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// teammatesQuery - кто из кандидатов был в одном составе заявки со зрителем.
+// Состав - автор заявки и принятые откликнувшиеся
+const teammatesQuery = `WITH rosters AS (
+	SELECT id AS application_id, user_id FROM game_applications
+	UNION
+	SELECT application_id, user_id FROM application_responses WHERE status = ?
+)
+SELECT DISTINCT other.user_id FROM rosters mine
+JOIN rosters other ON other.application_id = mine.application_id
+WHERE mine.user_id = ? AND other.user_id IN ?`
+
+// teammatesOf - множество тиммейтов зрителя среди candidates одним запросом
+func teammatesOf(viewerID uuid.UUID, candidates []uuid.UUID) map[uuid.UUID]bool {
+	teammates := make(map[uuid.UUID]bool)
+	if viewerID == uuid.Nil || len(candidates) == 0 {
+		return teammates
+	}
+
+	var ids []uuid.UUID
+	database.DB.Raw(teammatesQuery, models.StatusAccepted, viewerID, candidates).Scan(&ids)
+	for _, id := range ids {
+		teammates[id] = true
+	}
+	return teammates
+}
+
+// applyPrivacy скрывает у чужих профилей поля, которые владельцы не показывают зрителю.
+// Свой профиль зритель видит полностью; анонимный зритель - uuid.Nil
+func applyPrivacy(viewerID uuid.UUID, users ...*models.User) {
+	var others []*models.User
+	var candidates []uuid.UUID
+	for _, user := range users {
+		if user == nil || user.ID == uuid.Nil || user.ID == viewerID {
+			continue
+		}
+		others = append(others, user)
+		if user.EffectivePrivacy().HasTeammateFields() {
+			candidates = append(candidates, user.ID)
+		}
+	}
+
+	teammates := teammatesOf(viewerID, candidates)
+	for _, user := range others {
+		user.HidePrivateFields(teammates[user.ID])
+	}
+}
+
+// applicationUsers - авторы заявок
+func applicationUsers(applications []models.GameApplication) []*models.User {
+	users := make([]*models.User, 0, len(applications))
+	for i := range applications {
+		users = append(users, &applications[i].User)
+	}
+	return users
+}
+
+// responseUsers - авторы откликов, если они загружены
+func responseUsers(responses []models.ApplicationResponse) []*models.User {
+	users := make([]*models.User, 0, len(responses))
+	for i := range responses {
+		users = append(users, responses[i].User)
+	}
+	return users
+}
+
+// GetPrivacy - кому видны контакты и личные данные
+// GET /api/auth/me/privacy
+func GetPrivacy(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "privacy").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	return c.JSON(fiber.Map{
+		"privacy": user.EffectivePrivacy(),
+	})
+}
+
+// UpdatePrivacy - изменить видимость полей: {"privacy": {"discord": "nobody"}}.
+// Незаданные поля не меняются
+// PUT /api/auth/me/privacy
+func UpdatePrivacy(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		Privacy map[string]string `json:"privacy"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "privacy").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	privacy := user.EffectivePrivacy()
+	fields := map[string]*models.FieldVisibility{
+		"discord":    &privacy.Discord,
+		"telegram":   &privacy.Telegram,
+		"steam":      &privacy.Steam,
+		"birth_date": &privacy.BirthDate,
+		"gender":     &privacy.Gender,
+	}
+	for field, value := range req.Privacy {
+		target, ok := fields[field]
+		if !ok {
+			return apperror.InvalidValue("field").With("value", field)
+		}
+		visibility := models.FieldVisibility(value)
+		if !visibility.IsValid() {
+			return apperror.InvalidValue(field, "everyone", "teammates", "nobody")
+		}
+		*target = visibility
+	}
+
+	user.Privacy = &privacy
+	if err := database.DB.Model(&user).Select("privacy").Updates(&user).Error; err != nil {
+		return apperror.Internal("update privacy")
+	}
+
+	return c.JSON(fiber.Map{
+		"privacy": privacy,
+	})
+}
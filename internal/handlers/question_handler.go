@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuestionRequest - вопрос в теле создания/обновления заявки
+type QuestionRequest struct {
+	Type     string   `json:"type"`
+	Prompt   string   `json:"prompt"`
+	Options  []string `json:"options"`
+	Required *bool    `json:"required"`
+	MinValue *float64 `json:"min_value"`
+	MaxValue *float64 `json:"max_value"`
+}
+
+// AnswerRequest - ответ на вопрос в теле отклика
+type AnswerRequest struct {
+	QuestionID string   `json:"question_id"`
+	Text       *string  `json:"text"`
+	Choices    []string `json:"choices"`
+	Number     *float64 `json:"number"`
+}
+
+// buildApplicationQuestions валидирует вопросы из запроса и превращает их в модели
+func buildApplicationQuestions(reqs []QuestionRequest) ([]models.ApplicationQuestion, error) {
+	if len(reqs) > config.ResponseConfig.MaxQuestions {
+		return nil, apperror.ErrTooManyQuestions.With("max", config.ResponseConfig.MaxQuestions)
+	}
+
+	questions := make([]models.ApplicationQuestion, 0, len(reqs))
+	for i, req := range reqs {
+		questionType := models.QuestionType(req.Type)
+		if !questionType.IsValid() {
+			return nil, apperror.ErrQuestionInvalidType.WithDetails(fiber.Map{
+				"question": i + 1,
+				"allowed":  []string{"text", "single_choice", "multiple_choice", "number"},
+			})
+		}
+
+		prompt := strings.TrimSpace(req.Prompt)
+		if prompt == "" {
+			return nil, apperror.ErrQuestionNoPrompt.With("question", i+1)
+		}
+
+		question := models.ApplicationQuestion{
+			Position: i,
+			Type:     questionType,
+			Prompt:   prompt,
+			Required: req.Required == nil || *req.Required,
+		}
+
+		switch {
+		case questionType.IsChoice():
+			if len(req.Options) < 2 {
+				return nil, apperror.ErrQuestionFewOptions.With("question", i+1)
+			}
+			question.Options = req.Options
+		case questionType == models.QuestionNumber:
+			if req.MinValue != nil && req.MaxValue != nil && *req.MinValue > *req.MaxValue {
+				return nil, apperror.ErrQuestionInvalidRange.With("question", i+1)
+			}
+			question.MinValue = req.MinValue
+			question.MaxValue = req.MaxValue
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+// buildResponseAnswers проверяет ответы на вопросы заявки: обязательные заполнены,
+// варианты выбраны из списка, числа в допустимых границах
+func buildResponseAnswers(questions []models.ApplicationQuestion, reqs []AnswerRequest) ([]models.ResponseAnswer, error) {
+	byQuestion := make(map[uuid.UUID]AnswerRequest, len(reqs))
+	for _, req := range reqs {
+		questionID, err := uuid.Parse(req.QuestionID)
+		if err != nil {
+			return nil, apperror.InvalidID("question_id").With("value", req.QuestionID)
+		}
+		byQuestion[questionID] = req
+	}
+
+	answers := make([]models.ResponseAnswer, 0, len(questions))
+	for _, question := range questions {
+		req := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+
+		answer := models.ResponseAnswer{QuestionID: question.ID}
+		answered := false
+
+		switch question.Type {
+		case models.QuestionText:
+			if req.Text != nil && strings.TrimSpace(*req.Text) != "" {
+				text := strings.TrimSpace(*req.Text)
+				answer.Text = &text
+				answered = true
+			}
+		case models.QuestionSingleChoice, models.QuestionMultipleChoice:
+			if len(req.Choices) > 0 {
+				if question.Type == models.QuestionSingleChoice && len(req.Choices) != 1 {
+					return nil, apperror.ErrAnswerSingleChoice.With("question", question.Prompt)
+				}
+				for _, choice := range req.Choices {
+					if !question.HasOption(choice) {
+						return nil, apperror.ErrAnswerInvalidChoice.WithDetails(fiber.Map{"question": question.Prompt, "choice": choice})
+					}
+				}
+				answer.Choices = req.Choices
+				answered = true
+			}
+		case models.QuestionNumber:
+			if req.Number != nil {
+				if question.MinValue != nil && *req.Number < *question.MinValue {
+					return nil, apperror.ErrAnswerTooSmall.WithDetails(fiber.Map{"question": question.Prompt, "min": *question.MinValue})
+				}
+				if question.MaxValue != nil && *req.Number > *question.MaxValue {
+					return nil, apperror.ErrAnswerTooLarge.WithDetails(fiber.Map{"question": question.Prompt, "max": *question.MaxValue})
+				}
+				answer.Number = req.Number
+				answered = true
+			}
+		}
+
+		if !answered {
+			if question.Required {
+				return nil, apperror.ErrAnswerRequired.With("question", question.Prompt)
+			}
+			continue
+		}
+		answers = append(answers, answer)
+	}
+
+	if len(byQuestion) > 0 {
+		return nil, apperror.ErrAnswerUnknownQuestion
+	}
+
+	return answers, nil
+}
+
+// applyAnswerFilters фильтрует отклики по ответам на вопросы заявки.
+// Параметры запроса:
+//
+//	q_<question_id>=value      - текст содержит value / выбран вариант value
+//	q_<question_id>_min=1000   - числовой ответ не меньше
+//	q_<question_id>_max=3000   - числовой ответ не больше
+func applyAnswerFilters(c *fiber.Ctx, query *gorm.DB, questions []models.ApplicationQuestion) (*gorm.DB, error) {
+	byID := make(map[string]models.ApplicationQuestion, len(questions))
+	for _, question := range questions {
+		byID[question.ID.String()] = question
+	}
+
+	const answerExists = "EXISTS (SELECT 1 FROM response_answers ra WHERE ra.response_id = application_responses.id AND ra.question_id = ? AND "
+
+	for key, value := range c.Queries() {
+		if !strings.HasPrefix(key, "q_") || value == "" {
+			continue
+		}
+		rest := strings.TrimPrefix(key, "q_")
+
+		suffix := ""
+		if strings.HasSuffix(rest, "_min") || strings.HasSuffix(rest, "_max") {
+			suffix = rest[len(rest)-4:]
+			rest = rest[:len(rest)-4]
+		}
+
+		question, ok := byID[rest]
+		if !ok {
+			return nil, apperror.ErrFilterUnknownQuestion.With("question", rest)
+		}
+
+		switch {
+		case suffix != "":
+			if question.Type != models.QuestionNumber {
+				return nil, apperror.ErrFilterRangeUnsupported
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, apperror.ErrFilterInvalidNumber.With("filter", key)
+			}
+			op := ">="
+			if suffix == "_max" {
+				op = "<="
+			}
+			query = query.Where(answerExists+"ra.number "+op+" ?)", question.ID, number)
+		case question.Type.IsChoice():
+			choice, _ := json.Marshal([]string{value})
+			query = query.Where(answerExists+"ra.choices @> ?::jsonb)", question.ID, string(choice))
+		case question.Type == models.QuestionNumber:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, apperror.ErrFilterInvalidNumber.With("filter", key)
+			}
+			query = query.Where(answerExists+"ra.number = ?)", question.ID, number)
+		default:
+			query = query.Where(answerExists+"ra.text ILIKE ?)", question.ID, "%"+value+"%")
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// loadReportTarget находит объект жалобы и возвращает владельца контента и его снимок
+func loadReportTarget(db *gorm.DB, targetType models.ReportTargetType, targetID uuid.UUID) (uuid.UUID, string, error) {
+	var (
+		ownerID  uuid.UUID
+		snapshot interface{}
+	)
+
+	switch targetType {
+	case models.ReportTargetApplication:
+		var application models.GameApplication
+		if err := db.First(&application, targetID).Error; err != nil {
+			return uuid.Nil, "", err
+		}
+		ownerID = application.UserId
+		snapshot = fiber.Map{"title": application.Title, "description": application.Description}
+	case models.ReportTargetUser:
+		var user models.User
+		if err := db.First(&user, targetID).Error; err != nil {
+			return uuid.Nil, "", err
+		}
+		ownerID = user.ID
+		snapshot = fiber.Map{"nickname": user.Nickname, "description": user.Description}
+	case models.ReportTargetMessage:
+		var message models.Message
+		if err := db.First(&message, targetID).Error; err != nil {
+			return uuid.Nil, "", err
+		}
+		ownerID = message.SenderID
+		snapshot = fiber.Map{"conversation_id": message.ConversationID, "content": message.Content}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	return ownerID, string(data), nil
+}
+
+// CreateReport - пожаловаться на заявку, пользователя или сообщение
+// POST /api/reports
+func CreateReport(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		TargetType string  `json:"target_type"`
+		TargetID   string  `json:"target_id"`
+		ReasonCode string  `json:"reason_code"`
+		Details    *string `json:"details"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	targetType := models.ReportTargetType(req.TargetType)
+	if !targetType.IsValid() {
+		return apperror.InvalidValue("target_type", "application", "user", "message")
+	}
+
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		return apperror.InvalidID("target_id")
+	}
+
+	reason := models.ReportReason(req.ReasonCode)
+	if !reason.IsValid() {
+		return apperror.InvalidValue("reason")
+	}
+
+	ownerID, snapshot, err := loadReportTarget(database.DB, targetType, targetID)
+	if err != nil {
+		return apperror.ErrReportTargetNotFound
+	}
+
+	if ownerID == userID {
+		return apperror.ErrSelfAction.With("action", "report")
+	}
+
+	// На сообщения жалуются только участники диалога
+	if targetType == models.ReportTargetMessage {
+		var message models.Message
+		database.DB.Preload("Conversation").First(&message, targetID)
+		if message.Conversation == nil ||
+			(message.Conversation.Participant1ID != userID && message.Conversation.Participant2ID != userID) {
+			return apperror.ErrAccessDenied
+		}
+	}
+
+	// Повторная жалоба на тот же объект, пока первая не разобрана, не создается
+	var existing models.Report
+	err = database.DB.
+		Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status = ?", userID, targetType, targetID, models.ReportOpen).
+		First(&existing).Error
+	if err == nil {
+		return c.Status(fiber.StatusOK).JSON(existing)
+	}
+
+	report := models.Report{
+		ReporterID:     &userID,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetUserID:   ownerID,
+		ReasonCode:     reason,
+		Details:        req.Details,
+		TargetSnapshot: snapshot,
+		Status:         models.ReportOpen,
+	}
+	if err := database.DB.Create(&report).Error; err != nil {
+		return apperror.Internal("create report")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
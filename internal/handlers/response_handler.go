@@ -1,15 +1,281 @@
 package handlers
 
 import (
+	"errors"
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
 	"github.com/duker221/teamly/internal/database"
 	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/notifications"
+	"github.com/duker221/teamly/internal/services/webhooks"
+	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// findOrCreateConversation находит диалог между автором заявки и игроком или создает новый.
+// Найденный диалог возвращается из архива, т.к. в нем снова появляется активность
+func findOrCreateConversation(tx *gorm.DB, responseID, authorID, playerID uuid.UUID) (*models.Conversation, error) {
+	var conversation models.Conversation
+	now := time.Now()
+
+	// Проверяем существует ли уже conversation между этими пользователями
+	err := tx.Where(
+		"(participant1_id = ? AND participant2_id = ?) OR (participant1_id = ? AND participant2_id = ?)",
+		authorID, playerID, playerID, authorID,
+	).First(&conversation).Error
+
+	if err == gorm.ErrRecordNotFound {
+		// Conversation не найден - создаем новый
+		conversation = models.Conversation{
+			ResponseID:     responseID,
+			Participant1ID: authorID, // Автор заявки
+			Participant2ID: playerID, // Откликнувшийся
+			LastMessageAt:  &now,
+			IsArchived:     false,
+		}
+		if err := tx.Create(&conversation).Error; err != nil {
+			return nil, err
+		}
+		return &conversation, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Conversation найден - обновляем last_message_at и возвращаем из архива
+	conversation.LastMessageAt = &now
+	conversation.IsArchived = false
+	if err := tx.Save(&conversation).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// occupyApplicationSlot занимает место в заявке с блокировкой строки,
+// чтобы параллельные принятия не превысили MaxPlayers
+func occupyApplicationSlot(tx *gorm.DB, applicationID uuid.UUID) (*models.GameApplication, error) {
+	var application models.GameApplication
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, applicationID).Error; err != nil {
+		return nil, err
+	}
+
+	if !application.IsActive {
+		return nil, apperror.ErrApplicationInactive
+	}
+	if application.IsFull || application.AcceptedPlayers >= application.MaxPlayers {
+		return nil, apperror.ErrApplicationFull
+	}
+
+	application.AcceptedPlayers++
+	application.IsFull = application.AcceptedPlayers >= application.MaxPlayers
+
+	if err := tx.Save(&application).Error; err != nil {
+		return nil, err
+	}
+	// Последнее место занято - для внешних ботов заявка закрыта
+	if application.IsFull {
+		if err := webhooks.DispatchApplication(tx, models.WebhookApplicationClosed, application.ID, "full"); err != nil {
+			return nil, err
+		}
+	}
+	return &application, nil
+}
+
+// releaseApplicationSlot освобождает место в заявке, когда принятый игрок выбывает
+func releaseApplicationSlot(tx *gorm.DB, applicationID uuid.UUID) error {
+	var application models.GameApplication
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, applicationID).Error; err != nil {
+		return err
+	}
+
+	if application.AcceptedPlayers > 0 {
+		application.AcceptedPlayers--
+	}
+	application.IsFull = application.AcceptedPlayers >= application.MaxPlayers
+
+	return tx.Save(&application).Error
+}
+
+// applyResponseStatus переводит отклик в accepted/rejected, поддерживая счетчик
+// AcceptedPlayers и архивируя диалог отклоненного игрока
+func applyResponseStatus(tx *gorm.DB, response *models.ApplicationResponse, newStatus models.Status) error {
+	// Статус перечитывается под блокировкой строки: два параллельных принятия одного
+	// отклика иначе оба увидели бы pending и заняли бы два места
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(response, response.ID).Error; err != nil {
+		return err
+	}
+	if response.Status == newStatus {
+		return nil
+	}
+	// Отозванный отклик автор изменить уже не может
+	if response.Status == models.StatusWithdrawn {
+		return apperror.ErrResponseWithdrawn
+	}
+
+	switch {
+	case newStatus == models.StatusAccepted:
+		if _, err := occupyApplicationSlot(tx, response.ApplicationID); err != nil {
+			return err
+		}
+	case response.Status == models.StatusAccepted:
+		if err := releaseApplicationSlot(tx, response.ApplicationID); err != nil {
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{"status": newStatus, "status_changed_at": nil}
+	if newStatus == models.StatusRejected {
+		updates["status_changed_at"] = time.Now()
+	}
+	if err := tx.Model(response).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// Если отклонили - архивируем диалог
+	if newStatus == models.StatusRejected {
+		if err := tx.Model(&models.Conversation{}).
+			Where("response_id = ?", response.ID).
+			Update("is_archived", true).Error; err != nil {
+			return err
+		}
+	}
+
+	if newStatus == models.StatusAccepted {
+		if err := webhooks.DispatchResponse(tx, models.WebhookResponseAccepted, response.ID); err != nil {
+			return err
+		}
+	}
+
+	var application models.GameApplication
+	if err := tx.Select("id", "user_id", "title").First(&application, response.ApplicationID).Error; err != nil {
+		return err
+	}
+	return notifyResponseStatus(tx, &application, response.ID, response.UserID, newStatus)
+}
+
+// notifyResponseStatus сообщает игроку, что его отклик приняли или отклонили
+func notifyResponseStatus(tx *gorm.DB, application *models.GameApplication, responseID, playerID uuid.UUID, status models.Status) error {
+	notificationType := models.NotificationResponseAccepted
+	if status == models.StatusRejected {
+		notificationType = models.NotificationResponseRejected
+	}
+	return notifications.Notify(tx, models.Notification{
+		UserID:        playerID,
+		Type:          notificationType,
+		ActorID:       &application.UserId,
+		ApplicationID: &application.ID,
+		EntityID:      &responseID,
+		Data:          map[string]interface{}{"title": application.Title},
+	})
+}
+
+// rejectRemainingIfFull отклоняет все ожидающие отклики заполненной заявки.
+// Возвращает количество отклоненных
+func rejectRemainingIfFull(tx *gorm.DB, applicationID uuid.UUID) (int64, error) {
+	var application models.GameApplication
+	if err := tx.First(&application, applicationID).Error; err != nil {
+		return 0, err
+	}
+	if !application.IsFull {
+		return 0, nil
+	}
+
+	var pending []models.ApplicationResponse
+	if err := tx.Select("id", "user_id").
+		Where("application_id = ? AND status = ?", applicationID, models.StatusPending).
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	pendingIDs := make([]uuid.UUID, 0, len(pending))
+	for _, response := range pending {
+		pendingIDs = append(pendingIDs, response.ID)
+	}
+
+	if err := tx.Model(&models.ApplicationResponse{}).
+		Where("id IN ?", pendingIDs).
+		Updates(map[string]interface{}{
+			"status":            models.StatusRejected,
+			"status_changed_at": time.Now(),
+		}).Error; err != nil {
+		return 0, err
+	}
+
+	if err := tx.Model(&models.Conversation{}).
+		Where("response_id IN ?", pendingIDs).
+		Update("is_archived", true).Error; err != nil {
+		return 0, err
+	}
+
+	for _, response := range pending {
+		if err := notifyResponseStatus(tx, &application, response.ID, response.UserID, models.StatusRejected); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(pendingIDs)), nil
+}
+
+// responseStatusError оставляет ошибки каталога как есть, остальные считает внутренними
+func responseStatusError(err error) error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return apperror.ErrInternal.Wrap(err)
+}
+
+// setResponseStatus - принятие или отклонение отклика автором заявки.
+// Общая логика для API и кнопок Telegram-бота
+func setResponseStatus(userID, responseID uuid.UUID, newStatus models.Status, autoRejectRemaining bool) (*models.ApplicationResponse, error) {
+	// Получаем отклик с заявкой
+	var response models.ApplicationResponse
+	if err := database.DB.Preload("Application").First(&response, responseID).Error; err != nil {
+		return nil, apperror.ErrResponseNotFound
+	}
+
+	// Проверяем что пользователь - автор заявки
+	if response.Application.UserId != userID {
+		return nil, apperror.ErrNotApplicationAuthor
+	}
+
+	// Обновляем статус в транзакции
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := applyResponseStatus(tx, &response, newStatus); err != nil {
+		tx.Rollback()
+		return nil, responseStatusError(err)
+	}
+
+	// Заявка заполнилась - по желанию автора отклоняем остальных ожидающих
+	if newStatus == models.StatusAccepted && autoRejectRemaining {
+		if _, err := rejectRemainingIfFull(tx, response.ApplicationID); err != nil {
+			tx.Rollback()
+			return nil, apperror.Internal("reject remaining responses")
+		}
+	}
+
+	// Коммитим транзакцию
+	if err := tx.Commit().Error; err != nil {
+		return nil, apperror.Internal("commit transaction")
+	}
+
+	return &response, nil
+}
+
 // CreateApplicationResponse - создание отклика на заявку
 // POST /api/applications/:id/responses
 func CreateApplicationResponse(c *fiber.Ctx) error {
@@ -18,71 +284,90 @@ func CreateApplicationResponse(c *fiber.Ctx) error {
 	// Парсим ID заявки
 	appUUID, err := uuid.Parse(applicationID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid application ID",
-		})
+		return apperror.InvalidID("application_id")
 	}
 
 	// Получаем ID текущего пользователя из контекста (из JWT middleware)
 	userIDStr, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	// Парсим тело запроса
 	var req struct {
-		Message string `json:"message" validate:"required,min=10"`
+		Message   string          `json:"message" validate:"required,min=10"`
+		ShareCode string          `json:"share_code"`
+		Answers   []AnswerRequest `json:"answers"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	// Валидация: минимум 10 символов
 	if len(req.Message) < 10 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Message must be at least 10 characters long",
-		})
+		return apperror.Field(apperror.ErrTooShort, "message").With("min", 10)
+	}
+
+	filterResult, err := checkContent(req.Message, nil)
+	if err != nil {
+		return err
 	}
 
 	// Проверяем что заявка существует и активна
 	var application models.GameApplication
 	if err := database.DB.First(&application, appUUID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Application not found",
-		})
+		return apperror.ErrApplicationNotFound
 	}
 
-	if !application.IsActive {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Application is not active",
-		})
+	if !application.IsActive || application.IsHidden {
+		return apperror.ErrApplicationInactive
 	}
 
 	// Проверяем что пользователь не автор заявки
 	if application.UserId == userID {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot respond to your own application",
-		})
+		return apperror.ErrSelfAction.With("action", "respond")
+	}
+
+	// Заблокированный автором пользователь не может откликаться на его заявки
+	if isBlocked(application.UserId, userID) {
+		return apperror.ErrCannotRespond
 	}
 
-	// Проверяем что пользователь еще не откликался
-	var existingResponse models.ApplicationResponse
-	err = database.DB.Where("application_id = ? AND user_id = ?", appUUID, userID).First(&existingResponse).Error
+	// На скрытые заявки можно откликнуться только по действующему share-коду
+	if application.Visibility != models.VisibilityPublic &&
+		!utils.VerifyShareCode(req.ShareCode, application.ID, application.ShareNonce) {
+		return apperror.ErrShareCodeRequired
+	}
+
+	// Ответы на вопросы автора
+	var questions []models.ApplicationQuestion
+	database.DB.Where("application_id = ?", appUUID).Order("position ASC").Find(&questions)
+	answers, err := buildResponseAnswers(questions, req.Answers)
+	if err != nil {
+		return err
+	}
+
+	// Проверяем что пользователь еще не откликался.
+	// После отклонения или отзыва можно откликнуться повторно, но только по истечении cooldown
+	var existingResponse *models.ApplicationResponse
+	var previous models.ApplicationResponse
+	err = database.DB.Where("application_id = ? AND user_id = ?", appUUID, userID).First(&previous).Error
 	if err == nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "You have already responded to this application",
-		})
+		reapplyAt, canReapply := previous.CanReapplyAt(config.ResponseConfig.ReapplyCooldown)
+		if !canReapply {
+			return apperror.ErrAlreadyResponded
+		}
+		if time.Now().Before(reapplyAt) {
+			return apperror.ErrReapplyCooldown.With("can_reapply_at", reapplyAt)
+		}
+		existingResponse = &previous
+	} else if err != gorm.ErrRecordNotFound {
+		return apperror.Internal("check existing response")
 	}
 
 	// Транзакция: создаем Response + Conversation + Message
@@ -93,59 +378,48 @@ func CreateApplicationResponse(c *fiber.Ctx) error {
 		}
 	}()
 
-	// 1. Создаем отклик
-	response := models.ApplicationResponse{
-		ApplicationID: appUUID,
-		UserID:        userID,
-		Status:        models.StatusPending,
+	// 1. Создаем отклик (или переоткрываем прежний при повторном отклике)
+	var response models.ApplicationResponse
+	if existingResponse != nil {
+		response = *existingResponse
+		response.Status = models.StatusPending
+		response.StatusChangedAt = nil
+		if err := tx.Save(&response).Error; err != nil {
+			tx.Rollback()
+			return apperror.Internal("create response")
+		}
+	} else {
+		response = models.ApplicationResponse{
+			ApplicationID: appUUID,
+			UserID:        userID,
+			Status:        models.StatusPending,
+		}
+		if err := tx.Create(&response).Error; err != nil {
+			tx.Rollback()
+			return apperror.Internal("create response")
+		}
 	}
-	if err := tx.Create(&response).Error; err != nil {
+
+	// Сохраняем ответы (при повторном отклике старые заменяются)
+	if err := tx.Where("response_id = ?", response.ID).Delete(&models.ResponseAnswer{}).Error; err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create response",
-		})
+		return apperror.Internal("save answers")
 	}
-
-	// 2. Найти или создать диалог между двумя пользователями
-	var conversation models.Conversation
-	now := time.Now()
-
-	// Проверяем существует ли уже conversation между этими пользователями
-	err = tx.Where(
-		"(participant1_id = ? AND participant2_id = ?) OR (participant1_id = ? AND participant2_id = ?)",
-		application.UserId, userID, userID, application.UserId,
-	).First(&conversation).Error
-
-	if err == gorm.ErrRecordNotFound {
-		// Conversation не найден - создаем новый
-		conversation = models.Conversation{
-			ResponseID:     response.ID,
-			Participant1ID: application.UserId, // Автор заявки
-			Participant2ID: userID,             // Откликнувшийся
-			LastMessageAt:  &now,
-			IsArchived:     false,
+	if len(answers) > 0 {
+		for i := range answers {
+			answers[i].ResponseID = response.ID
 		}
-		if err := tx.Create(&conversation).Error; err != nil {
+		if err := tx.Create(&answers).Error; err != nil {
 			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to create conversation",
-			})
+			return apperror.Internal("save answers")
 		}
-	} else if err != nil {
-		// Другая ошибка
+	}
+
+	// 2. Найти или создать диалог между двумя пользователями
+	conversation, err := findOrCreateConversation(tx, response.ID, application.UserId, userID)
+	if err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to check conversation",
-		})
-	} else {
-		// Conversation найден - обновляем last_message_at
-		conversation.LastMessageAt = &now
-		if err := tx.Save(&conversation).Error; err != nil {
-			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to update conversation",
-			})
-		}
+		return apperror.Internal("create conversation")
 	}
 
 	// 3. Создаем первое сообщение (сопроводительное письмо)
@@ -157,20 +431,40 @@ func CreateApplicationResponse(c *fiber.Ctx) error {
 	}
 	if err := tx.Create(&message).Error; err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create message",
-		})
+		return apperror.Internal("create message")
+	}
+
+	// 4. Уведомляем автора заявки
+	if err := notifications.Notify(tx, models.Notification{
+		UserID:        application.UserId,
+		Type:          models.NotificationNewResponse,
+		ActorID:       &userID,
+		ApplicationID: &application.ID,
+		EntityID:      &response.ID,
+		Data:          map[string]interface{}{"title": application.Title},
+	}); err != nil {
+		tx.Rollback()
+		return apperror.Internal("create notification")
+	}
+
+	// 5. Событие для вебхуков автора
+	if err := webhooks.DispatchResponse(tx, models.WebhookResponseCreated, response.ID); err != nil {
+		tx.Rollback()
+		return apperror.Internal("dispatch webhooks")
 	}
 
 	// Коммитим транзакцию
 	if err := tx.Commit().Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to commit transaction",
-		})
+		return apperror.Internal("commit transaction")
 	}
 
+	flagForReview(filterResult, models.ReportTargetResponse, response.ID, userID,
+		fiber.Map{"application_id": appUUID, "message": req.Message})
+
 	// Загружаем связанные данные для ответа
-	database.DB.Preload("User").Preload("Application").Preload("Conversation").First(&response, response.ID)
+	database.DB.Preload("User").Preload("Application").Preload("Conversation").Preload("Answers").First(&response, response.ID)
+	response.HideAuthorFields()
+	applyPrivacy(userID, response.User)
 
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
@@ -182,59 +476,86 @@ func GetApplicationResponses(c *fiber.Ctx) error {
 
 	appUUID, err := uuid.Parse(applicationID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid application ID",
-		})
+		return apperror.InvalidID("application_id")
 	}
 
 	// Получаем ID текущего пользователя
 	userIDStr, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	// Проверяем что заявка существует
 	var application models.GameApplication
 	if err := database.DB.First(&application, appUUID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Application not found",
-		})
+		return apperror.ErrApplicationNotFound
 	}
 
 	// Проверяем что пользователь - автор заявки
 	if application.UserId != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "You can only view responses to your own applications",
-		})
+		return apperror.ErrNotApplicationAuthor
 	}
 
-	// Получаем отклики с первым сообщением
-	var responses []models.ApplicationResponse
-	err = database.DB.
+	// Получаем отклики с первым сообщением и ответами на вопросы.
+	// К откликнувшимся подгружается их профиль в игре заявки
+	query := database.DB.
 		Preload("User").
+		Preload("User.GameProfiles", "game_id = ?", application.GameId).
+		Preload("Answers").
 		Preload("Conversation", "is_archived = ? OR is_archived = ?", false, true). // Загружаем все диалоги
 		Preload("Conversation.Messages", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at ASC").Limit(1) // Только первое сообщение
 		}).
-		Where("application_id = ?", appUUID).
-		Order("created_at DESC").
-		Find(&responses).Error
+		Where("application_id = ?", appUUID)
 
+	// Фильтры для разбора откликов автором
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if c.Query("starred") == "true" {
+		query = query.Where("is_starred = ?", true)
+	}
+	if c.Query("shortlisted") == "true" {
+		query = query.Where("is_shortlisted = ?", true)
+	}
+
+	// ?min_reliability=80 - только игроки с надежностью не ниже порога.
+	// Игроки без отметок посещаемости попадают в выборку только с include_unrated=true
+	if minReliability := c.QueryFloat("min_reliability", -1); minReliability >= 0 {
+		reliable := "attended_count + no_show_count > 0 AND attended_count * 100.0 / (attended_count + no_show_count) >= ?"
+		if c.Query("include_unrated") == "true" {
+			reliable = "(attended_count + no_show_count = 0 OR (" + reliable + "))"
+		}
+		query = query.Where("user_id IN (?)",
+			database.DB.Model(&models.User{}).Select("id").Where(reliable, minReliability))
+	}
+
+	query, err = applyGameProfileFilters(c, query, application.GameId)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch responses",
-		})
+		return err
 	}
 
+	// Фильтрация по ответам: ?q_<question_id>=...
+	var questions []models.ApplicationQuestion
+	database.DB.Where("application_id = ?", appUUID).Find(&questions)
+	query, err = applyAnswerFilters(c, query, questions)
+	if err != nil {
+		return err
+	}
+
+	var responses []models.ApplicationResponse
+	err = query.Order("created_at DESC").Find(&responses).Error
+
+	if err != nil {
+		return apperror.Internal("fetch responses")
+	}
+	applyPrivacy(userID, responseUsers(responses)...)
+
 	return c.JSON(responses)
 }
 
@@ -245,34 +566,27 @@ func UpdateResponseStatus(c *fiber.Ctx) error {
 
 	respUUID, err := uuid.Parse(responseID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid response ID",
-		})
+		return apperror.InvalidID("response_id")
 	}
 
 	// Получаем ID текущего пользователя
 	userIDStr, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	// Парсим тело запроса
 	var req struct {
-		Status string `json:"status" validate:"required,oneof=accepted rejected"`
+		Status              string `json:"status" validate:"required,oneof=accepted rejected"`
+		AutoRejectRemaining bool   `json:"auto_reject_remaining"`
 	}
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	// Проверяем валидность статуса
@@ -283,27 +597,227 @@ func UpdateResponseStatus(c *fiber.Ctx) error {
 	case "rejected":
 		newStatus = models.StatusRejected
 	default:
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid status. Must be 'accepted' or 'rejected'",
-		})
+		return apperror.InvalidValue("status", "accepted", "rejected")
+	}
+
+	response, err := setResponseStatus(userID, respUUID, newStatus, req.AutoRejectRemaining)
+	if err != nil {
+		return err
+	}
+
+	// Загружаем связанные данные перед возвратом
+	database.DB.
+		Preload("Application").
+		Preload("Application.Game").
+		Preload("User").
+		Preload("Conversation.Messages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC").Limit(1)
+		}).
+		First(response, response.ID)
+	applyPrivacy(userID, response.User)
+
+	return c.JSON(response)
+}
+
+// BulkUpdateResponseStatus - принять/отклонить сразу несколько откликов.
+// Все изменения применяются атомарно: если мест не хватает, не меняется ничего
+// POST /api/applications/:id/responses/bulk
+func BulkUpdateResponseStatus(c *fiber.Ctx) error {
+	application, err := loadOwnApplication(c)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		ResponseIDs         []string `json:"response_ids"`
+		Status              string   `json:"status"`
+		AutoRejectRemaining bool     `json:"auto_reject_remaining"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	newStatus := models.Status(req.Status)
+	if newStatus != models.StatusAccepted && newStatus != models.StatusRejected {
+		return apperror.InvalidValue("status", "accepted", "rejected")
+	}
+
+	if len(req.ResponseIDs) == 0 {
+		return apperror.Required("response_ids")
+	}
+
+	// Повторы в списке не считаются отдельными откликами
+	responseIDs := make([]uuid.UUID, 0, len(req.ResponseIDs))
+	seen := make(map[uuid.UUID]bool, len(req.ResponseIDs))
+	for _, id := range req.ResponseIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return apperror.InvalidID("response_ids").With("value", id)
+		}
+		if !seen[parsed] {
+			seen[parsed] = true
+			responseIDs = append(responseIDs, parsed)
+		}
+	}
+
+	var responses []models.ApplicationResponse
+	var autoRejected int64
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Отклики блокируются в порядке id, чтобы параллельные массовые изменения
+		// не ждали друг друга по кругу; статусы и места проверяются по свежим строкам
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND application_id = ?", responseIDs, application.ID).
+			Order("id").
+			Find(&responses).Error; err != nil {
+			return err
+		}
+
+		// Все отклики должны относиться к этой заявке
+		if len(responses) != len(responseIDs) {
+			return apperror.ErrResponseNotFound
+		}
+
+		// Заранее проверяем, хватит ли мест, чтобы вернуть понятную ошибку
+		if newStatus == models.StatusAccepted {
+			toAccept := 0
+			for _, response := range responses {
+				if response.Status != models.StatusAccepted {
+					toAccept++
+				}
+			}
+			var current models.GameApplication
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "max_players", "accepted_players").
+				First(&current, application.ID).Error; err != nil {
+				return err
+			}
+			if free := current.MaxPlayers - current.AcceptedPlayers; toAccept > free {
+				return apperror.ErrNotEnoughSlots.WithDetails(fiber.Map{
+					"free_slots": free,
+					"requested":  toAccept,
+				})
+			}
+		}
+
+		for i := range responses {
+			if err := applyResponseStatus(tx, &responses[i], newStatus); err != nil {
+				return err
+			}
+		}
+
+		if newStatus == models.StatusAccepted && req.AutoRejectRemaining {
+			rejected, err := rejectRemainingIfFull(tx, application.ID)
+			if err != nil {
+				return err
+			}
+			autoRejected = rejected
+		}
+		return nil
+	})
+	if err != nil {
+		return responseStatusError(err)
+	}
+
+	database.DB.First(application, application.ID)
+
+	return c.JSON(fiber.Map{
+		"updated_count":       len(responses),
+		"auto_rejected_count": autoRejected,
+		"application":         application,
+	})
+}
+
+// UpdateResponseTriage - приватные пометки автора: заметка, звезда, шорт-лист
+// PATCH /api/responses/:id/triage
+func UpdateResponseTriage(c *fiber.Ctx) error {
+	respUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("response_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		AuthorNote    *string `json:"author_note"`
+		IsStarred     *bool   `json:"is_starred"`
+		IsShortlisted *bool   `json:"is_shortlisted"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
 	}
 
-	// Получаем отклик с заявкой
 	var response models.ApplicationResponse
 	if err := database.DB.Preload("Application").First(&response, respUUID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Response not found",
-		})
+		return apperror.ErrResponseNotFound
 	}
 
-	// Проверяем что пользователь - автор заявки
 	if response.Application.UserId != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Only the application author can update response status",
-		})
+		return apperror.ErrNotApplicationAuthor
+	}
+
+	updates := map[string]interface{}{}
+	if req.AuthorNote != nil {
+		if *req.AuthorNote == "" {
+			updates["author_note"] = nil
+		} else {
+			updates["author_note"] = *req.AuthorNote
+		}
+	}
+	if req.IsStarred != nil {
+		updates["is_starred"] = *req.IsStarred
+	}
+	if req.IsShortlisted != nil {
+		updates["is_shortlisted"] = *req.IsShortlisted
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&response).Updates(updates).Error; err != nil {
+			return apperror.Internal("update response")
+		}
+	}
+
+	database.DB.Preload("User").Preload("Answers").First(&response, response.ID)
+	applyPrivacy(userID, response.User)
+
+	return c.JSON(response)
+}
+
+// WithdrawApplicationResponse - отозвать свой отклик
+// POST /api/responses/:id/withdraw
+func WithdrawApplicationResponse(c *fiber.Ctx) error {
+	respUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("response_id")
+	}
+
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var response models.ApplicationResponse
+	if err := database.DB.First(&response, respUUID).Error; err != nil {
+		return apperror.ErrResponseNotFound
+	}
+
+	// Отозвать может только сам откликнувшийся
+	if response.UserID != userID {
+		return apperror.ErrNotResponseOwner
 	}
 
-	// Обновляем статус в транзакции
 	tx := database.DB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -311,68 +825,55 @@ func UpdateResponseStatus(c *fiber.Ctx) error {
 		}
 	}()
 
-	// Обновляем статус отклика
-	response.Status = newStatus
-	if err := tx.Save(&response).Error; err != nil {
+	// Статус перечитывается под блокировкой строки: автор мог принять отклик
+	// одновременно с отзывом, и тогда занятое место нужно освободить
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&response, respUUID).Error; err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update response status",
-		})
+		return apperror.ErrResponseNotFound
+	}
+	if response.Status != models.StatusPending && response.Status != models.StatusAccepted {
+		tx.Rollback()
+		return apperror.ErrResponseNotWithdrawable
 	}
 
-	// Если приняли - увеличиваем счетчик игроков
-	if newStatus == models.StatusAccepted {
-		var application models.GameApplication
-		if err := tx.First(&application, response.Application.ID).Error; err != nil {
-			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to fetch application",
-			})
-		}
-
-		// Увеличиваем счетчик
-		application.AcceptedPlayers++
+	wasAccepted := response.Status == models.StatusAccepted
 
-		// Проверяем заполненность
-		if application.AcceptedPlayers >= application.MaxPlayers {
-			application.IsFull = true
-		}
+	// Меняем только статус: пометки автора могли измениться после чтения
+	now := time.Now()
+	response.Status = models.StatusWithdrawn
+	response.StatusChangedAt = &now
+	if err := tx.Model(&response).Select("status", "status_changed_at").Updates(&response).Error; err != nil {
+		tx.Rollback()
+		return apperror.Internal("withdraw response")
+	}
 
-		if err := tx.Save(&application).Error; err != nil {
+	// Если отклик был принят - освобождаем место в заявке
+	if wasAccepted {
+		if err := releaseApplicationSlot(tx, response.ApplicationID); err != nil {
 			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to update application",
-			})
+			return apperror.Internal("update application")
 		}
 	}
 
-	// Если отклонили - архивируем диалог
-	if newStatus == models.StatusRejected {
-		var conversation models.Conversation
-		if err := tx.Where("response_id = ?", respUUID).First(&conversation).Error; err == nil {
-			conversation.IsArchived = true
-			tx.Save(&conversation)
-		}
+	// Архивируем диалог, как и при отклонении
+	var conversation models.Conversation
+	if err := tx.Where("response_id = ?", respUUID).First(&conversation).Error; err == nil {
+		conversation.IsArchived = true
+		tx.Save(&conversation)
 	}
 
-	// Коммитим транзакцию
 	if err := tx.Commit().Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to commit transaction",
-		})
+		return apperror.Internal("commit transaction")
 	}
 
-	// Загружаем связанные данные перед возвратом
-	database.DB.
-		Preload("Application").
-		Preload("Application.Game").
-		Preload("User").
-		Preload("Conversation.Messages", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at ASC").Limit(1)
-		}).
-		First(&response, response.ID)
+	reapplyAt, _ := response.CanReapplyAt(config.ResponseConfig.ReapplyCooldown)
+	response.HideAuthorFields()
 
-	return c.JSON(response)
+	return c.JSON(fiber.Map{
+		"message":        "Response withdrawn successfully",
+		"response":       response,
+		"can_reapply_at": reapplyAt,
+	})
 }
 
 // GetMyResponses - получить мои отклики на чужие заявки
@@ -380,16 +881,12 @@ func UpdateResponseStatus(c *fiber.Ctx) error {
 func GetMyResponses(c *fiber.Ctx) error {
 	userIDStr, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	var responses []models.ApplicationResponse
@@ -405,9 +902,11 @@ func GetMyResponses(c *fiber.Ctx) error {
 		Find(&responses).Error
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch responses",
-		})
+		return apperror.Internal("fetch responses")
+	}
+
+	for i := range responses {
+		responses[i].HideAuthorFields()
 	}
 
 	return c.JSON(responses)
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/notifications"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// isUniqueViolation - запись не вставилась из-за уникального индекса index.
+// Так проявляется гонка двух одинаковых запросов, прошедших предварительную проверку
+func isUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == index
+}
+
+// isOnRoster - пользователь в составе заявки: ее автор или принятый игрок
+func isOnRoster(db *gorm.DB, application *models.GameApplication, userID uuid.UUID) bool {
+	if application.UserId == userID {
+		return true
+	}
+
+	var count int64
+	db.Model(&models.ApplicationResponse{}).
+		Where("application_id = ? AND user_id = ? AND status = ?", application.ID, userID, models.StatusAccepted).
+		Count(&count)
+	return count > 0
+}
+
+// changeReviewCounter меняет likes_count/dislikes_count пользователя на delta
+func changeReviewCounter(tx *gorm.DB, userID uuid.UUID, reviewType models.ReviewType, delta int) error {
+	column := reviewType.CounterColumn()
+	return tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumn(column, gorm.Expr("GREATEST("+column+" + ?, 0)", delta)).Error
+}
+
+// CreateReview - оставить отзыв игроку из общего состава заявки
+// POST /api/reviews
+func CreateReview(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req struct {
+		ReviewedUserID string  `json:"reviewed_user_id"`
+		ApplicationID  string  `json:"application_id"`
+		ReviewType     string  `json:"review_type"`
+		Comment        *string `json:"comment"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	reviewedUserID, err := uuid.Parse(req.ReviewedUserID)
+	if err != nil {
+		return apperror.InvalidID("reviewed_id")
+	}
+
+	applicationID, err := uuid.Parse(req.ApplicationID)
+	if err != nil {
+		return apperror.InvalidID("application_id")
+	}
+
+	reviewType := models.ReviewType(req.ReviewType)
+	if !reviewType.IsValid() {
+		return apperror.InvalidValue("type", "like", "dislike")
+	}
+
+	if reviewedUserID == userID {
+		return apperror.ErrSelfAction.With("action", "review")
+	}
+
+	var application models.GameApplication
+	if err := database.DB.First(&application, applicationID).Error; err != nil {
+		return apperror.ErrApplicationNotFound
+	}
+
+	// Оценивать можно только тех, с кем был в одном составе
+	if !isOnRoster(database.DB, &application, userID) || !isOnRoster(database.DB, &application, reviewedUserID) {
+		return apperror.ErrNotTeammate
+	}
+
+	var existing int64
+	database.DB.Model(&models.Review{}).
+		Where("reviewer_id = ? AND reviewed_user_id = ? AND application_id = ?", userID, reviewedUserID, applicationID).
+		Count(&existing)
+	if existing > 0 {
+		return apperror.ErrAlreadyReviewed
+	}
+
+	review := models.Review{
+		ReviewerID:     userID,
+		ReviewedUserID: reviewedUserID,
+		ApplicationID:  applicationID,
+		ReviewType:     reviewType,
+		Comment:        req.Comment,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if err := changeReviewCounter(tx, reviewedUserID, reviewType, 1); err != nil {
+			return err
+		}
+		return notifications.Notify(tx, models.Notification{
+			UserID:        reviewedUserID,
+			Type:          models.NotificationReviewReceived,
+			ActorID:       &userID,
+			ApplicationID: &applicationID,
+			EntityID:      &review.ID,
+			Data:          map[string]interface{}{"review_type": reviewType},
+		})
+	})
+	if isUniqueViolation(err, "idx_review_pair") {
+		return apperror.ErrAlreadyReviewed
+	}
+	if err != nil {
+		return apperror.Internal("create review")
+	}
+
+	database.DB.Preload("Reviewer").First(&review, review.ID)
+	applyPrivacy(userID, review.Reviewer)
+
+	return c.Status(fiber.StatusCreated).JSON(review)
+}
+
+// UpdateReview - изменить свой отзыв в пределах окна редактирования
+// PATCH /api/reviews/:id
+func UpdateReview(c *fiber.Ctx) error {
+	review, err := loadOwnReview(c)
+	if err != nil {
+		return err
+	}
+
+	if !review.IsEditable(config.ReviewConfig.EditWindow) {
+		return apperror.ErrReviewLocked
+	}
+
+	var req struct {
+		ReviewType *string `json:"review_type"`
+		Comment    *string `json:"comment"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	oldType := review.ReviewType
+	if req.ReviewType != nil {
+		newType := models.ReviewType(*req.ReviewType)
+		if !newType.IsValid() {
+			return apperror.InvalidValue("type", "like", "dislike")
+		}
+		review.ReviewType = newType
+	}
+	if req.Comment != nil {
+		review.Comment = req.Comment
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(review).Error; err != nil {
+			return err
+		}
+		if oldType == review.ReviewType {
+			return nil
+		}
+		// Тип изменился - переносим голос между счетчиками
+		if err := changeReviewCounter(tx, review.ReviewedUserID, oldType, -1); err != nil {
+			return err
+		}
+		return changeReviewCounter(tx, review.ReviewedUserID, review.ReviewType, 1)
+	})
+	if err != nil {
+		return apperror.Internal("update review")
+	}
+
+	return c.JSON(review)
+}
+
+// DeleteReview - удалить свой отзыв
+// DELETE /api/reviews/:id
+func DeleteReview(c *fiber.Ctx) error {
+	review, err := loadOwnReview(c)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(review).Error; err != nil {
+			return err
+		}
+		return changeReviewCounter(tx, review.ReviewedUserID, review.ReviewType, -1)
+	})
+	if err != nil {
+		return apperror.Internal("delete review")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Review deleted successfully",
+	})
+}
+
+// GetUserReviews - отзывы о пользователе с пагинацией
+// GET /api/users/:id/reviews?page=1&limit=20&type=like
+func GetUserReviews(c *fiber.Ctx) error {
+	reviewedUserID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperror.InvalidID("user_id")
+	}
+
+	query := database.DB.Model(&models.Review{}).Where("reviewed_user_id = ?", reviewedUserID)
+	if reviewType := c.Query("type"); reviewType != "" {
+		query = query.Where("review_type = ?", reviewType)
+	}
+
+	var reviews []models.Review
+	page, err := paginate(c, query, &reviews, pageLimits{Default: 20, Max: 50}, func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload("Reviewer", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "nickname", "avatar_url")
+			}).
+			Preload("Application", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "title", "game_id")
+			}).
+			Order("created_at DESC")
+	})
+	if err != nil {
+		return apperror.Internal("fetch reviews")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reviews": reviews,
+		"count":   len(reviews),
+		"total":   page.Total,
+		"page":    page.Page,
+		"limit":   page.Limit,
+	})
+}
+
+// loadOwnReview загружает отзыв из :id и проверяет, что его автор - текущий пользователь.
+// Ошибки возвращаются как *apperror.Error и оформляются общим ErrorHandler
+func loadOwnReview(c *fiber.Ctx) (*models.Review, error) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	reviewID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, apperror.InvalidID("review_id")
+	}
+
+	var review models.Review
+	if err := database.DB.First(&review, reviewID).Error; err != nil {
+		return nil, apperror.ErrReviewNotFound
+	}
+
+	if review.ReviewerID != userID {
+		return nil, apperror.ErrNotReviewOwner
+	}
+
+	return &review, nil
+}
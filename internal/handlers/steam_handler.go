@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/steam"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSteam - привязанный аккаунт Steam и данные профиля (библиотека, часы)
+// GET /api/steam
+func GetSteam(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "steam_id", "steam_linked_at").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.SteamID == nil {
+		return apperror.ErrSteamNotLinked
+	}
+
+	profile := steam.CachedProfile(userID)
+	if config.SteamConfig.StatsEnabled() {
+		if fresh, err := steam.Profile(userID); err == nil {
+			profile = fresh
+		} else {
+			log.Printf("Failed to load Steam profile for user %s: %v", userID, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"steam_id":      user.SteamID,
+		"linked_at":     user.SteamLinkedAt,
+		"stats_enabled": config.SteamConfig.StatsEnabled(),
+		"profile":       profile,
+	})
+}
+
+// StartSteamLink - ссылка на вход через Steam. Фронтенд переводит по ней пользователя,
+// Steam возвращает его на SteamCallback
+// POST /api/steam/link
+func StartSteamLink(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	nonce, err := steam.NewNonce()
+	if err != nil {
+		return apperror.Internal("generate steam login nonce")
+	}
+	setSteamNonceCookie(c, nonce, int(config.SteamConfig.LinkTTL.Seconds()))
+
+	return c.JSON(fiber.Map{
+		"url":        steam.LoginURL(userID, nonce),
+		"expires_in": int(config.SteamConfig.LinkTTL.Seconds()),
+	})
+}
+
+// SteamCallback - возврат из Steam после входа. Пользователь определяется по state,
+// а не по cookie авторизации: это переход браузера со стороннего сайта. Cookie с nonce
+// подтверждает, что привязку начал этот же браузер.
+// Результат передается фронтенду параметром steam=linked или steam=error&reason=...
+// GET /api/steam/callback
+func SteamCallback(c *fiber.Ctx) error {
+	query := url.Values{}
+	for key, value := range c.Queries() {
+		query.Set(key, value)
+	}
+
+	nonce := c.Cookies(steamNonceCookie)
+	// Ссылка одноразовая: повторный возврат с тем же state не пройдет
+	setSteamNonceCookie(c, "", -1)
+
+	userID, steamID, err := steam.VerifyLogin(query, nonce)
+	if err != nil {
+		reason := "rejected"
+		if errors.Is(err, steam.ErrInvalidState) {
+			reason = "invalid_state"
+		}
+		log.Printf("Steam login verification failed: %v", err)
+		return redirectSteamResult(c, reason)
+	}
+
+	if err := steam.Link(userID, steamID); err != nil {
+		if errors.Is(err, steam.ErrAccountTaken) {
+			return redirectSteamResult(c, "account_taken")
+		}
+		log.Printf("Failed to link Steam account for user %s: %v", userID, err)
+		return redirectSteamResult(c, "internal")
+	}
+
+	// Библиотеку загружаем сразу, чтобы часы подтвердились без отдельного запроса
+	if config.SteamConfig.StatsEnabled() {
+		if _, err := steam.Refresh(userID); err != nil {
+			log.Printf("Failed to import Steam profile for user %s: %v", userID, err)
+		}
+	}
+
+	return redirectSteamResult(c, "")
+}
+
+// steamNonceCookie - cookie с nonce входа через Steam, отправляется только на callback
+const steamNonceCookie = "steam_login_nonce"
+
+// setSteamNonceCookie ставит cookie с nonce; отрицательный maxAge удаляет ее.
+// SameSite=Lax: при переходе из Steam на callback cookie отправляется
+func setSteamNonceCookie(c *fiber.Ctx, nonce string, maxAge int) {
+	c.Cookie(&fiber.Cookie{
+		Name:     steamNonceCookie,
+		Value:    nonce,
+		Path:     steamCallbackPath(),
+		MaxAge:   maxAge,
+		HTTPOnly: true,
+		Secure:   os.Getenv("GO_ENV") == "production",
+		SameSite: "Lax",
+	})
+}
+
+// steamCallbackPath - путь callback из STEAM_CALLBACK_URL: за прокси он может отличаться
+func steamCallbackPath() string {
+	callback, err := url.Parse(config.SteamConfig.CallbackURL)
+	if err != nil || callback.Path == "" {
+		return "/api/steam/callback"
+	}
+	return callback.Path
+}
+
+func redirectSteamResult(c *fiber.Ctx, reason string) error {
+	params := url.Values{"steam": {"linked"}}
+	if reason != "" {
+		params = url.Values{"steam": {"error"}, "reason": {reason}}
+	}
+	return c.Redirect(config.SteamConfig.RedirectURL+"?"+params.Encode(), fiber.StatusFound)
+}
+
+// RefreshSteam - загрузить данные из Steam заново, не дожидаясь истечения кеша
+// POST /api/steam/refresh
+func RefreshSteam(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+	if !config.SteamConfig.StatsEnabled() {
+		return apperror.ErrSteamStatsDisabled
+	}
+
+	profile, err := steam.Refresh(userID)
+	switch {
+	case errors.Is(err, steam.ErrNotLinked):
+		return apperror.ErrSteamNotLinked
+	case errors.Is(err, steam.ErrRefreshTooSoon):
+		return apperror.ErrSteamRefreshTooSoon.With("minutes", int(config.SteamConfig.RefreshCooldown.Minutes()))
+	case err != nil:
+		log.Printf("Failed to refresh Steam profile for user %s: %v", userID, err)
+		return apperror.ErrSteamUnavailable
+	}
+
+	return c.JSON(profile)
+}
+
+// UnlinkSteam - отвязать Steam. Подтвержденные часы в игровых профилях снимаются
+// DELETE /api/steam/link
+func UnlinkSteam(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := steam.Unlink(userID); err != nil {
+		if errors.Is(err, steam.ErrNotLinked) {
+			return apperror.ErrSteamNotLinked
+		}
+		return apperror.Internal("unlink steam")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Steam account unlinked",
+	})
+}
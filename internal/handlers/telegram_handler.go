@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/suspension"
+	"github.com/duker221/teamly/internal/services/telegram"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTelegramLink - привязан ли Telegram к текущему пользователю
+// GET /api/telegram/link
+func GetTelegramLink(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "telegram_chat_id", "telegram_linked_at").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+
+	return c.JSON(fiber.Map{
+		"enabled":      config.TelegramConfig.Enabled(),
+		"linked":       user.TelegramChatID != nil,
+		"linked_at":    user.TelegramLinkedAt,
+		"bot_username": config.TelegramConfig.BotUsername,
+	})
+}
+
+// CreateTelegramLinkCode - одноразовый код привязки. Пользователь отправляет его боту
+// командой /start <код> или просто открывает ссылку link
+// POST /api/telegram/link
+func CreateTelegramLinkCode(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+	if !config.TelegramConfig.Enabled() {
+		return apperror.ErrTelegramDisabled
+	}
+
+	code, expiresAt, err := telegram.CreateLinkCode(database.DB, userID)
+	if err != nil {
+		return apperror.Internal("create telegram link code")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code":       code,
+		"link":       telegram.DeepLink(code),
+		"expires_at": expiresAt,
+	})
+}
+
+// DeleteTelegramLink - отвязать Telegram от аккаунта
+// DELETE /api/telegram/link
+func DeleteTelegramLink(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "telegram_chat_id").First(&user, userID).Error; err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.TelegramChatID == nil {
+		return apperror.ErrTelegramNotLinked
+	}
+
+	if err := telegram.Unlink(database.DB, userID); err != nil {
+		return apperror.Internal("unlink telegram")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Telegram unlinked",
+	})
+}
+
+// TelegramWebhook - обновления от Bot API: команды в личном чате и нажатия кнопок.
+// Ошибки обработки только логируются - иначе Telegram будет повторять обновление.
+// Без TELEGRAM_WEBHOOK_SECRET запросы не принимаются: их мог бы прислать кто угодно
+// POST /api/telegram/webhook
+func TelegramWebhook(c *fiber.Ctx) error {
+	cfg := config.TelegramConfig
+	if !cfg.WebhookEnabled() {
+		return apperror.ErrTelegramDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Get("X-Telegram-Bot-Api-Secret-Token")), []byte(cfg.WebhookSecret)) != 1 {
+		return apperror.ErrUnauthorized
+	}
+
+	var update telegram.Update
+	if err := c.BodyParser(&update); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	switch {
+	case update.CallbackQuery != nil:
+		handleTelegramCallback(update.CallbackQuery)
+	case update.Message != nil && update.Message.Chat.Type == "private":
+		handleTelegramMessage(update.Message)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// handleTelegramMessage обрабатывает команды /start <код>, /link <код> и /unlink
+func handleTelegramMessage(message *telegram.Message) {
+	chatID := message.Chat.ID
+	locale := ""
+	username := ""
+	if message.From != nil {
+		locale = message.From.LanguageCode
+		username = message.From.Username
+	}
+	user, err := telegram.UserByChat(database.DB, chatID)
+	if err == nil {
+		locale = user.Locale
+	}
+
+	command, argument, _ := strings.Cut(strings.TrimSpace(message.Text), " ")
+	// Команда может прийти в виде /start@имя_бота
+	command, _, _ = strings.Cut(command, "@")
+	argument = strings.TrimSpace(argument)
+
+	switch {
+	case (command == "/start" || command == "/link") && argument != "":
+		linked, err := telegram.Link(database.DB, argument, chatID, username)
+		if errors.Is(err, telegram.ErrInvalidLinkCode) {
+			replyTelegram(chatID, telegram.T(locale, "link.invalid_code", nil))
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to link Telegram chat %d: %v", chatID, err)
+			return
+		}
+		replyTelegram(chatID, telegram.T(linked.Locale, "link.success", map[string]any{"Nickname": linked.Nickname}))
+	case command == "/unlink" || command == "/stop":
+		if user == nil {
+			replyTelegram(chatID, telegram.T(locale, "not_linked", nil))
+			return
+		}
+		if err := telegram.Unlink(database.DB, user.ID); err != nil {
+			log.Printf("Failed to unlink Telegram of user %s: %v", user.ID, err)
+			return
+		}
+		replyTelegram(chatID, telegram.T(locale, "unlink.success", nil))
+	case user != nil:
+		replyTelegram(chatID, telegram.T(locale, "help", nil))
+	default:
+		replyTelegram(chatID, telegram.T(locale, "start.help", nil))
+	}
+}
+
+// handleTelegramCallback - кнопки "Принять"/"Отклонить" под уведомлением о новом отклике.
+// Статус меняется той же логикой, что и PATCH /api/responses/:id
+func handleTelegramCallback(query *telegram.CallbackQuery) {
+	action, responseID, ok := telegram.ParseCallbackData(query.Data)
+	if !ok || query.Message == nil {
+		answerTelegramCallback(query.ID, "")
+		return
+	}
+
+	chatID := query.Message.Chat.ID
+	user, err := telegram.UserByChat(database.DB, chatID)
+	if err != nil {
+		answerTelegramCallback(query.ID, telegram.T(query.From.LanguageCode, "not_linked", nil))
+		return
+	}
+	// Заблокированный пользователь не может отвечать на отклики и через бота
+	var suspended *suspension.Error
+	if errors.As(suspension.Of(user), &suspended) {
+		answerTelegramCallback(query.ID, apperror.ErrAccountSuspended.WithDetails(suspended.Details()).Message(user.Locale))
+		return
+	}
+
+	status, resultKey := models.StatusAccepted, "response.accepted"
+	if action == telegram.ActionReject {
+		status, resultKey = models.StatusRejected, "response.rejected"
+	}
+
+	if _, err := setResponseStatus(user.ID, responseID, status, false); err != nil {
+		appErr := apperror.From(err)
+		if appErr.Status >= fiber.StatusInternalServerError {
+			log.Printf("Telegram callback for response %s failed: %v", responseID, err)
+		}
+		answerTelegramCallback(query.ID, appErr.Message(user.Locale))
+		return
+	}
+
+	result := telegram.T(user.Locale, resultKey, nil)
+	answerTelegramCallback(query.ID, result)
+	// Убираем кнопки и дописываем итог в исходное сообщение
+	if err := telegram.EditMessageText(chatID, query.Message.MessageID, query.Message.Text+"\n\n"+result); err != nil {
+		log.Printf("Failed to edit Telegram message %d: %v", query.Message.MessageID, err)
+	}
+}
+
+func replyTelegram(chatID int64, text string) {
+	if err := telegram.SendMessage(chatID, text, nil); err != nil {
+		log.Printf("Failed to reply to Telegram chat %d: %v", chatID, err)
+	}
+}
+
+func answerTelegramCallback(callbackID, text string) {
+	if err := telegram.AnswerCallbackQuery(callbackID, text); err != nil {
+		log.Printf("Failed to answer Telegram callback: %v", err)
+	}
+}
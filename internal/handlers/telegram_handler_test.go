@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+func withTelegramConfig(t *testing.T, cfg config.TelegramBotConfig) {
+	t.Helper()
+	previous := config.TelegramConfig
+	config.TelegramConfig = cfg
+	t.Cleanup(func() { config.TelegramConfig = previous })
+}
+
+// telegramWebhookStatus - код ответа webhook на пустое обновление с заголовком secret
+func telegramWebhookStatus(t *testing.T, secret string) int {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: apperror.Handler})
+	app.Post("/api/telegram/webhook", TelegramWebhook)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api/telegram/webhook", strings.NewReader("{}"))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if secret != "" {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("webhook request: %v", err)
+	}
+	return resp.StatusCode
+}
+
+// Без настроенного секрета обновления не принимаются вовсе - даже с пустым заголовком
+func TestTelegramWebhookRequiresConfiguredSecret(t *testing.T) {
+	withTelegramConfig(t, config.TelegramBotConfig{BotToken: "token"})
+
+	for _, secret := range []string{"", "anything"} {
+		if status := telegramWebhookStatus(t, secret); status == fiber.StatusOK {
+			t.Errorf("update with secret %q accepted without TELEGRAM_WEBHOOK_SECRET", secret)
+		}
+	}
+}
+
+func TestTelegramWebhookChecksSecretHeader(t *testing.T) {
+	withTelegramConfig(t, config.TelegramBotConfig{BotToken: "token", WebhookSecret: "webhook-secret"})
+
+	cases := map[string]struct {
+		secret string
+		want   int
+	}{
+		"missing": {"", fiber.StatusUnauthorized},
+		"wrong":   {"other-secret", fiber.StatusUnauthorized},
+		"valid":   {"webhook-secret", fiber.StatusOK},
+	}
+	for name, tc := range cases {
+		if status := telegramWebhookStatus(t, tc.secret); status != tc.want {
+			t.Errorf("%s secret: status %d, want %d", name, status, tc.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/webhooks"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookRequest struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	GameID      *string  `json:"game_id"` // пустая строка снимает фильтр по игре
+	Description *string  `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// apply валидирует запрос и переносит заданные поля в вебхук
+func (req *WebhookRequest) apply(hook *models.Webhook) error {
+	if req.URL != nil {
+		url := strings.TrimSpace(*req.URL)
+		if err := webhooks.ValidateURL(url); err != nil {
+			return apperror.ErrInvalidWebhookURL.With("reason", err.Error())
+		}
+		hook.URL = url
+	}
+
+	if req.Events != nil {
+		if len(req.Events) == 0 {
+			return apperror.Required("events")
+		}
+		events := make([]models.WebhookEvent, 0, len(req.Events))
+		for _, raw := range req.Events {
+			event := models.WebhookEvent(raw)
+			if !event.IsValid() {
+				allowed := make([]string, 0, len(models.WebhookEvents))
+				for _, event := range models.WebhookEvents {
+					allowed = append(allowed, string(event))
+				}
+				return apperror.InvalidValue("events", allowed...)
+			}
+			events = append(events, event)
+		}
+		hook.Events = events
+	}
+
+	if req.GameID != nil {
+		hook.GameID = nil
+		if *req.GameID != "" {
+			gameID, err := uuid.Parse(*req.GameID)
+			if err != nil {
+				return apperror.InvalidID("game_id")
+			}
+			var game models.Game
+			if err := database.DB.Select("id").First(&game, gameID).Error; err != nil {
+				return apperror.ErrGameNotFound
+			}
+			hook.GameID = &gameID
+		}
+	}
+
+	if req.Description != nil {
+		hook.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// CreateWebhook - регистрация вебхука. Ключ подписи возвращается только здесь
+// POST /api/webhooks
+func CreateWebhook(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+	if req.URL == nil || req.Events == nil {
+		return apperror.Required("url", "events")
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return apperror.ErrUnauthorized
+	}
+	if user.Role != models.RoleAdmin {
+		var count int64
+		database.DB.Model(&models.Webhook{}).Where("owner_id = ?", userID).Count(&count)
+		if int(count) >= config.WebhookConfig.MaxPerUser {
+			return apperror.ErrWebhookLimit.With("max", config.WebhookConfig.MaxPerUser)
+		}
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return apperror.Internal("generate webhook secret")
+	}
+
+	hook := models.Webhook{
+		OwnerID:  userID,
+		Secret:   secret,
+		IsActive: true,
+	}
+	if err := req.apply(&hook); err != nil {
+		return err
+	}
+
+	if err := database.DB.Create(&hook).Error; err != nil {
+		return apperror.Internal("create webhook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"webhook": hook,
+		"secret":  secret,
+	})
+}
+
+// GetWebhooks - вебхуки текущего пользователя; админ с ?all=true видит все
+// GET /api/webhooks
+func GetWebhooks(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	query := database.DB.Model(&models.Webhook{})
+	if !c.QueryBool("all", false) || !isAdmin(userID) {
+		query = query.Where("owner_id = ?", userID)
+	}
+
+	var hooks []models.Webhook
+	if err := query.Order("created_at DESC").Find(&hooks).Error; err != nil {
+		return apperror.Internal("fetch webhooks")
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": hooks,
+		"events":   models.WebhookEvents,
+	})
+}
+
+// UpdateWebhook - изменение адреса, событий, фильтра по игре или отключение
+// PATCH /api/webhooks/:id
+func UpdateWebhook(c *fiber.Ctx) error {
+	hook, err := loadWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+	if err := req.apply(hook); err != nil {
+		return err
+	}
+
+	if err := database.DB.Save(hook).Error; err != nil {
+		return apperror.Internal("update webhook")
+	}
+
+	return c.JSON(hook)
+}
+
+// DeleteWebhook - удаление вебхука вместе с журналом доставок
+// DELETE /api/webhooks/:id
+func DeleteWebhook(c *fiber.Ctx) error {
+	hook, err := loadWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", hook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(hook).Error
+	})
+	if err != nil {
+		return apperror.Internal("delete webhook")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Webhook deleted",
+	})
+}
+
+// GetWebhookDeliveries - журнал доставок вебхука, новые сверху
+// GET /api/webhooks/:id/deliveries?status=dead&page=1&limit=20
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	hook, err := loadWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	query := database.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", hook.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	page, err := paginate(c, query, &deliveries, pageLimits{Default: 20, Max: 100}, func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at DESC")
+	})
+	if err != nil {
+		return apperror.Internal("fetch webhook deliveries")
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+		"total":      page.Total,
+		"page":       page.Page,
+		"limit":      page.Limit,
+	})
+}
+
+// PingWebhook - тестовая доставка события ping, результат возвращается сразу
+// POST /api/webhooks/:id/ping
+func PingWebhook(c *fiber.Ctx) error {
+	hook, err := loadWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := webhooks.Ping(database.DB, hook)
+	if err != nil {
+		return apperror.Internal("ping webhook")
+	}
+
+	return c.JSON(delivery)
+}
+
+// RetryWebhookDelivery - вернуть неудавшуюся доставку в очередь
+// POST /api/webhooks/:id/deliveries/:deliveryId/retry
+func RetryWebhookDelivery(c *fiber.Ctx) error {
+	hook, err := loadWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	deliveryID, err := uuid.Parse(c.Params("deliveryId"))
+	if err != nil {
+		return apperror.InvalidID("delivery_id")
+	}
+
+	var delivery models.WebhookDelivery
+	if err := database.DB.Where("id = ? AND webhook_id = ?", deliveryID, hook.ID).First(&delivery).Error; err != nil {
+		return apperror.ErrWebhookDeliveryNotFound
+	}
+	if delivery.Status != models.WebhookDeliveryDead || delivery.Event == models.WebhookPing {
+		return apperror.ErrDeliveryNotRetryable
+	}
+
+	if err := database.DB.Model(&delivery).Updates(map[string]interface{}{
+		"status":          models.WebhookDeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error; err != nil {
+		return apperror.Internal("retry webhook delivery")
+	}
+
+	return c.JSON(delivery)
+}
+
+// loadWebhook загружает вебхук из :id. Доступ есть у владельца и админов
+func loadWebhook(c *fiber.Ctx) (*models.Webhook, error) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	hookID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, apperror.InvalidID("webhook_id")
+	}
+
+	var hook models.Webhook
+	if err := database.DB.First(&hook, hookID).Error; err != nil {
+		return nil, apperror.ErrWebhookNotFound
+	}
+	if hook.OwnerID != userID && !isAdmin(userID) {
+		return nil, apperror.ErrWebhookNotFound
+	}
+
+	return &hook, nil
+}
+
+func isAdmin(userID uuid.UUID) bool {
+	var user models.User
+	if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return false
+	}
+	return user.Role == models.RoleAdmin
+}
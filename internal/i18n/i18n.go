@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"text/template"
+
+	"github.com/duker221/teamly/internal/utils"
+)
+
+// Bundle - переводы одного раздела (ошибки API, письма, бот): по JSON-файлу на язык
+// в каталоге locales, значения - text/template строки с плейсхолдерами вида {{.Count}}
+type Bundle struct {
+	name    string
+	locales map[string]map[string]*template.Template
+}
+
+// MustLoad загружает locales/<язык>.json из fsys для всех поддерживаемых языков.
+// Отсутствующий или некорректный файл - паника при старте
+func MustLoad(name string, fsys fs.FS, funcs template.FuncMap) *Bundle {
+	bundle := &Bundle{name: name, locales: make(map[string]map[string]*template.Template)}
+	for _, locale := range utils.SupportedLocales {
+		raw, err := fs.ReadFile(fsys, "locales/"+locale+".json")
+		if err != nil {
+			panic(fmt.Sprintf("%s: missing locale %s: %v", name, locale, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("%s: invalid locale %s: %v", name, locale, err))
+		}
+
+		templates := make(map[string]*template.Template, len(messages))
+		for key, message := range messages {
+			templates[key] = template.Must(template.New(key).Funcs(funcs).Option("missingkey=zero").Parse(message))
+		}
+		bundle.locales[locale] = templates
+	}
+	return bundle
+}
+
+// Has - есть ли перевод ключа на язык locale
+func (b *Bundle) Has(locale, key string) bool {
+	_, ok := b.locales[locale][key]
+	return ok
+}
+
+// T возвращает перевод ключа на язык locale, подставляя data в плейсхолдеры.
+// Если перевода нет, берется язык по умолчанию, а затем сам ключ
+func (b *Bundle) T(locale, key string, data any) string {
+	message, ok := b.locales[utils.NormalizeLocale(locale)][key]
+	if !ok {
+		if message, ok = b.locales[utils.DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := message.Execute(&buf, data); err != nil {
+		return key
+	}
+	return buf.String()
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"errors"
 	"os"
 
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/services/suspension"
 	"github.com/duker221/teamly/internal/utils"
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -13,20 +16,37 @@ func JWTProtected(c *fiber.Ctx) error {
 		SigningKey: jwtware.SigningKey{Key: []byte(os.Getenv("JWT_SECRET_KEY"))},
 		ContextKey: "jwt",
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": true,
-				"msg":   err.Error(),
-			})
+			return apperror.ErrUnauthorized.Wrap(err)
 		},
 	})(c)
 }
 
+// Identify определяет пользователя по токену запроса и проверяет его блокировку.
+// Заблокированный пользователь не может пользоваться ранее выданными токенами:
+// для обработчиков его запросы анонимны, а AuthRequired отвечает account_suspended
+func Identify(c *fiber.Ctx) error {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		return c.Next()
+	}
+
+	if err := suspension.Check(userID); err != nil {
+		c.Locals(utils.AuthErrorLocal, err)
+		return c.Next()
+	}
+
+	c.Locals("userID", userID.String())
+	return c.Next()
+}
+
 func AuthRequired(c *fiber.Ctx) error {
 	userID, err := utils.GetUserIDFromContext(c)
+	var suspended *suspension.Error
+	if errors.As(err, &suspended) {
+		return apperror.ErrAccountSuspended.WithDetails(suspended.Details())
+	}
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "User not authenticated",
-		})
+		return apperror.ErrUnauthorized
 	}
 
 	c.Locals("userID", userID.String())
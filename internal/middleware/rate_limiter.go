@@ -3,6 +3,7 @@ package middleware
 import (
 	"time"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 )
@@ -17,9 +18,7 @@ func AuthRateLimiter() fiber.Handler {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Слишком много попыток. Подождите минуту.",
-			})
+			return apperror.ErrTooManyAttempts
 		},
 	})
 }
@@ -34,9 +33,7 @@ func APIRateLimiter() fiber.Handler {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Слишком много запросов. Попробуйте позже.",
-			})
+			return apperror.ErrTooManyRequests
 		},
 	})
 }
@@ -51,9 +48,7 @@ func CreateApplicationRateLimiter() fiber.Handler {
 			return c.IP()
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Достигнут лимит создания заявок. Попробуйте через час.",
-			})
+			return apperror.ErrApplicationLimit
 		},
 	})
 }
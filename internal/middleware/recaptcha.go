@@ -3,6 +3,7 @@ package middleware
 import (
 	"log"
 
+	"github.com/duker221/teamly/internal/apperror"
 	"github.com/duker221/teamly/internal/utils"
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,13 +13,13 @@ func RecaptchaMiddleware() fiber.Handler {
 		token := c.Get("X-Recaptcha-Token")
 		log.Println(token)
 		if token == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "recaptcha token missing"})
+			return apperror.ErrRecaptchaMissing
 		}
 
 		ok, err := utils.VerifyRecaptcha(token)
 		log.Println(ok, err)
 		if err != nil || !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "recaptcha failed"})
+			return apperror.ErrRecaptchaFailed
 		}
 
 		return c.Next()
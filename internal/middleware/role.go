@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/duker221/teamly/internal/apperror"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ModeratorRequired пропускает только модераторов и админов.
+// Должен стоять после AuthRequired
+func ModeratorRequired(c *fiber.Ctx) error {
+	var user models.User
+	if err := database.DB.Select("id", "role").Where("id = ?", c.Locals("userID")).First(&user).Error; err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	if !user.Role.CanModerate() {
+		return apperror.ErrModeratorRequired
+	}
+
+	return c.Next()
+}
+
+// AdminRequired пропускает только админов.
+// Должен стоять после AuthRequired
+func AdminRequired(c *fiber.Ctx) error {
+	var user models.User
+	if err := database.DB.Select("id", "role").Where("id = ?", c.Locals("userID")).First(&user).Error; err != nil {
+		return apperror.ErrUnauthorized
+	}
+
+	if user.Role != models.RoleAdmin {
+		return apperror.ErrAdminRequired
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttendanceStatus string
+
+const (
+	AttendanceAttended AttendanceStatus = "attended"
+	AttendanceNoShow   AttendanceStatus = "no_show"
+)
+
+func (s AttendanceStatus) IsValid() bool {
+	return s == AttendanceAttended || s == AttendanceNoShow
+}
+
+// CounterColumn - какой счетчик пользователя соответствует отметке
+func (s AttendanceStatus) CounterColumn() string {
+	if s == AttendanceNoShow {
+		return "no_show_count"
+	}
+	return "attended_count"
+}
+
+// AttendanceReport - отметка участника состава о том, пришел ли другой игрок на игру
+type AttendanceReport struct {
+	ID             uuid.UUID        `gorm:"primaryKey" json:"id"`
+	ApplicationID  uuid.UUID        `gorm:"not null;uniqueIndex:idx_attendance_pair" json:"application_id"`
+	ReporterID     uuid.UUID        `gorm:"not null;uniqueIndex:idx_attendance_pair" json:"reporter_id"`
+	ReportedUserID uuid.UUID        `gorm:"not null;uniqueIndex:idx_attendance_pair;index" json:"reported_user_id"`
+	ReportedUser   *User            `gorm:"foreignKey:ReportedUserID" json:"reported_user,omitempty"`
+	Status         AttendanceStatus `gorm:"not null;size:10" json:"status"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (r *AttendanceReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
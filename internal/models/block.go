@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserBlock - пользователь BlockerID заблокировал пользователя BlockedID
+type UserBlock struct {
+	ID        uuid.UUID `gorm:"primaryKey" json:"id"`
+	BlockerID uuid.UUID `gorm:"not null;uniqueIndex:idx_block_pair" json:"blocker_id"`
+	BlockedID uuid.UUID `gorm:"not null;uniqueIndex:idx_block_pair;index" json:"blocked_id"`
+	Blocked   *User     `gorm:"foreignKey:BlockedID" json:"blocked,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (b *UserBlock) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dryRunDB - GORM с диалектом Postgres, который только строит SQL и не подключается к БД
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=localhost user=test dbname=test sslmode=disable"), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("open dry-run db: %v", err)
+	}
+	return db
+}
+
+// insertedValue - значение колонки в INSERT, который GORM строит для value
+func insertedValue(t *testing.T, value any, column string) (any, bool) {
+	t.Helper()
+	stmt := dryRunDB(t).Create(value).Statement
+	values, ok := stmt.Clauses["VALUES"].Expression.(clause.Values)
+	if !ok || len(values.Values) == 0 {
+		t.Fatalf("no VALUES clause in %q", stmt.SQL.String())
+	}
+	for i, col := range values.Columns {
+		if col.Name == column {
+			return values.Values[0][i], true
+		}
+	}
+	return nil, false
+}
+
+// Явно выключенный флаг должен попадать в INSERT: с тегом default GORM пропускает
+// нулевое значение, и в БД оказывается значение по умолчанию
+func assertInsertedFalse(t *testing.T, value any, column string) {
+	t.Helper()
+	inserted, ok := insertedValue(t, value, column)
+	if !ok {
+		t.Fatalf("column %s is missing from INSERT", column)
+	}
+	if inserted != false {
+		t.Fatalf("column %s inserted as %v, want false", column, inserted)
+	}
+}
+
+func TestOptionalQuestionIsStoredAsOptional(t *testing.T) {
+	assertInsertedFalse(t, &ApplicationQuestion{Type: QuestionText, Prompt: "Discord?", Required: false}, "required")
+}
+
+func TestDisabledWebhookIsStoredAsDisabled(t *testing.T) {
+	assertInsertedFalse(t, &Webhook{URL: "https://example.com/hook", Secret: "secret", IsActive: false}, "is_active")
+}
+
+func TestPausedDiscordChannelIsStoredAsPaused(t *testing.T) {
+	assertInsertedFalse(t, &DiscordChannel{WebhookID: "1", WebhookToken: "token", Locale: "en", IsActive: false}, "is_active")
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DiscordChannel - канал анонсов игры в Discord, подключенный через webhook канала.
+// Публикуются публичные заявки, созданные после подключения
+type DiscordChannel struct {
+	ID     uuid.UUID `gorm:"primaryKey" json:"id"`
+	GameID uuid.UUID `gorm:"not null;uniqueIndex" json:"game_id"`
+	Game   *Game     `gorm:"foreignKey:GameID" json:"game,omitempty"`
+	// Из URL вебхука https://discord.com/api/webhooks/<id>/<token>; токен дает право писать в канал
+	WebhookID    string `gorm:"size:32;not null" json:"webhook_id"`
+	WebhookToken string `gorm:"size:100;not null" json:"-"`
+	// Язык подписей в анонсах (ru, en)
+	Locale   string `gorm:"size:5;default:'ru'" json:"locale"`
+	IsActive bool   `json:"is_active"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (c *DiscordChannel) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+type DiscordPostState string
+
+const (
+	DiscordPostPending DiscordPostState = "pending" // еще не опубликован
+	DiscordPostOpen    DiscordPostState = "open"    // опубликован, набор идет
+	DiscordPostClosed  DiscordPostState = "closed"  // заявка заполнилась, анонс помечен закрытым
+	DiscordPostRemoved DiscordPostState = "removed" // заявка истекла, удалена или скрыта - сообщение удалено
+	DiscordPostFailed  DiscordPostState = "failed"  // исчерпаны попытки синхронизации
+)
+
+// DiscordPost - сообщение с анонсом заявки в канале. Воркер приводит сообщение
+// к текущему состоянию заявки, когда она меняется или истекает
+type DiscordPost struct {
+	ID            uuid.UUID        `gorm:"primaryKey" json:"id"`
+	ChannelID     uuid.UUID        `gorm:"not null;uniqueIndex:idx_discord_post_application" json:"channel_id"`
+	ApplicationID uuid.UUID        `gorm:"not null;uniqueIndex:idx_discord_post_application" json:"application_id"`
+	MessageID     string           `gorm:"size:32" json:"message_id,omitempty"`
+	State         DiscordPostState `gorm:"size:20;default:'pending';index" json:"state"`
+	// updated_at заявки, с которым сообщение синхронизировано последний раз
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+
+	Attempts      int       `gorm:"default:0" json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     *string   `gorm:"type:text" json:"last_error,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *DiscordPost) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.NextAttemptAt.IsZero() {
+		p.NextAttemptAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	EmailDead    EmailStatus = "dead"    // исчерпаны попытки доставки
+	EmailSkipped EmailStatus = "skipped" // отправка писем выключена
+)
+
+// EmailOutbox - письмо в очереди на отправку. Пишется в той же транзакции,
+// что и изменение, которое его вызвало, и доставляется фоновым воркером
+type EmailOutbox struct {
+	ID      uuid.UUID         `gorm:"primaryKey" json:"id"`
+	Kind    string            `gorm:"size:40;index" json:"kind"` // password_reset, notification, digest
+	To      string            `gorm:"column:recipient;not null;index" json:"to"`
+	Subject string            `gorm:"not null" json:"subject"`
+	HTML    string            `gorm:"type:text" json:"-"`
+	Text    string            `gorm:"type:text" json:"-"`
+	Headers map[string]string `gorm:"type:jsonb;serializer:json" json:"-"`
+
+	Status        EmailStatus `gorm:"size:20;default:'pending';index:idx_outbox_due" json:"status"`
+	Attempts      int         `gorm:"default:0" json:"attempts"`
+	NextAttemptAt time.Time   `gorm:"index:idx_outbox_due" json:"next_attempt_at"`
+	LastError     *string     `gorm:"type:text" json:"last_error,omitempty"`
+	ProviderID    *string     `json:"provider_id,omitempty"`
+	SentAt        *time.Time  `json:"sent_at,omitempty"`
+	// Когда тело письма стерто: после доставки оно не нужно, а в письмах бывают
+	// ссылки-секреты (сброс пароля)
+	RedactedAt *time.Time `json:"redacted_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Redact стирает тело и заголовки письма, оставляя запись для журнала
+func (e *EmailOutbox) Redact(now time.Time) {
+	e.HTML = ""
+	e.Text = ""
+	e.Headers = nil
+	e.RedactedAt = &now
+}
+
+func (e *EmailOutbox) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailMode - как доставлять письма о событии
+type EmailMode string
+
+const (
+	EmailInstant EmailMode = "instant" // ближайшей рассылкой
+	EmailDigest  EmailMode = "digest"  // раз в сутки одним письмом
+	EmailOff     EmailMode = "off"
+)
+
+func (m EmailMode) IsValid() bool {
+	switch m {
+	case EmailInstant, EmailDigest, EmailOff:
+		return true
+	}
+	return false
+}
+
+// EmailEventTypes - уведомления, о которых можно получать письма
+var EmailEventTypes = []NotificationType{
+	NotificationNewResponse,
+	NotificationResponseAccepted,
+	NotificationNewMessage,
+}
+
+// IsEmailEvent - можно ли получать письма о событии этого типа
+func (t NotificationType) IsEmailEvent() bool {
+	for _, eventType := range EmailEventTypes {
+		if eventType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultEmailMode - режим, если пользователь ничего не выбирал.
+// Сообщений много, поэтому по умолчанию они приходят дайджестом
+func DefaultEmailMode(t NotificationType) EmailMode {
+	if t == NotificationNewMessage {
+		return EmailDigest
+	}
+	return EmailInstant
+}
+
+// EmailPreference - выбранный пользователем режим писем для типа события
+type EmailPreference struct {
+	ID        uuid.UUID        `gorm:"primaryKey" json:"-"`
+	UserID    uuid.UUID        `gorm:"not null;uniqueIndex:idx_email_preference" json:"-"`
+	EventType NotificationType `gorm:"not null;size:40;uniqueIndex:idx_email_preference" json:"event_type"`
+	Mode      EmailMode        `gorm:"not null;size:20" json:"mode"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *EmailPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ type Game struct {
 	Updated_at time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	Slug       string    `gorm:"not null" json:"slug"`
 	IsActive   bool      `gorm:"default:true" json:"is_active"`
+	SteamAppID *int      `gorm:"uniqueIndex" json:"steam_app_id,omitempty"` // ID приложения в Steam для подтверждения часов
 }
 
 func (g *Game) BeforeCreate(tx *gorm.DB) error {
@@ -17,12 +17,32 @@ const (
 	PlatformMobile         Platform = "mobile"
 )
 
+// Visibility - кто может видеть заявку. Доступ к unlisted и private устроен одинаково:
+// вне ленты и профиля, открываются по share-коду, а без него - только автору и участникам.
+// Различие лишь в том, как клиент подписывает заявку
+type Visibility string
+
+const (
+	VisibilityPublic   Visibility = "public"   // В общей ленте
+	VisibilityUnlisted Visibility = "unlisted" // Не в ленте, раздается ссылкой с share-кодом
+	VisibilityPrivate  Visibility = "private"  // Закрытый набор, тоже только по share-коду
+)
+
+func (v Visibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
 type Status string
 
 const (
-	StatusPending  Status = "pending"
-	StatusAccepted Status = "accepted"
-	StatusRejected Status = "rejected"
+	StatusPending   Status = "pending"
+	StatusAccepted  Status = "accepted"
+	StatusRejected  Status = "rejected"
+	StatusWithdrawn Status = "withdrawn"
 )
 
 type GameApplication struct {
@@ -45,8 +65,16 @@ type GameApplication struct {
 	IsActive      bool `gorm:"default:true;index" json:"is_active"`
 	IsFull        bool `gorm:"default:false" json:"is_full"`
 	WithVoiceChat bool `gorm:"default:false" json:"with_voice_chat"`
+	IsHidden      bool `gorm:"default:false;index" json:"is_hidden"` // Скрыта модератором
+
+	Platform   Platform   `gorm:"default:pc" json:"platform"`
+	Visibility Visibility `gorm:"default:'public';index" json:"visibility"`
+	// Случайная соль share-кода; смена соли отзывает все выданные ссылки
+	ShareNonce string `gorm:"size:32" json:"-"`
+
+	// Вопросы для откликающихся
+	Questions []ApplicationQuestion `gorm:"foreignKey:ApplicationID" json:"questions,omitempty"`
 
-	Platform  Platform  `gorm:"default:pc" json:"platform"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -58,13 +86,22 @@ type ApplicationResponse struct {
 	UserID        uuid.UUID        `gorm:"not null;index" json:"user_id"` // Кто откликнулся
 	User          *User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
 
-	Status        Status           `gorm:"default:'pending';index" json:"status"`
+	Status          Status     `gorm:"default:'pending';index" json:"status"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"` // Когда отклик отклонили/отозвали
 
-	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	// Приватные пометки автора заявки, откликнувшийся их не видит
+	AuthorNote    *string `gorm:"type:text" json:"author_note,omitempty"`
+	IsStarred     bool    `gorm:"default:false" json:"is_starred"`
+	IsShortlisted bool    `gorm:"default:false" json:"is_shortlisted"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Ответы на вопросы заявки
+	Answers []ResponseAnswer `gorm:"foreignKey:ResponseID" json:"answers,omitempty"`
 
 	// Связь 1:1 с Conversation
-	Conversation  *Conversation    `gorm:"foreignKey:ResponseID" json:"conversation,omitempty"`
+	Conversation *Conversation `gorm:"foreignKey:ResponseID" json:"conversation,omitempty"`
 }
 
 func (ga *GameApplication) BeforeCreate(tx *gorm.DB) error {
@@ -83,3 +120,23 @@ func (ar *ApplicationResponse) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// HideAuthorFields убирает приватные пометки автора перед отдачей отклика самому откликнувшемуся
+func (ar *ApplicationResponse) HideAuthorFields() {
+	ar.AuthorNote = nil
+	ar.IsStarred = false
+	ar.IsShortlisted = false
+}
+
+// CanReapplyAt возвращает момент, начиная с которого можно откликнуться повторно.
+// Для активных откликов (pending/accepted) повторный отклик невозможен.
+func (ar *ApplicationResponse) CanReapplyAt(cooldown time.Duration) (time.Time, bool) {
+	if ar.Status != StatusRejected && ar.Status != StatusWithdrawn {
+		return time.Time{}, false
+	}
+	changedAt := ar.UpdatedAt
+	if ar.StatusChangedAt != nil {
+		changedAt = *ar.StatusChangedAt
+	}
+	return changedAt.Add(cooldown), true
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserGameProfile - игровой профиль пользователя в конкретной игре:
+// "Dota 2: 4500 MMR, pos 4/5, 3000 часов". Один профиль на игру
+type UserGameProfile struct {
+	ID     uuid.UUID `gorm:"primaryKey" json:"id"`
+	UserID uuid.UUID `gorm:"not null;uniqueIndex:idx_user_game_profile" json:"user_id"`
+	GameID uuid.UUID `gorm:"not null;uniqueIndex:idx_user_game_profile;index" json:"game_id"`
+	Game   *Game     `gorm:"foreignKey:GameID" json:"game,omitempty"`
+
+	// Ранг как его пишут в игре ("Divine 3", "Faceit 8") и числовой рейтинг (MMR, ELO) для фильтров
+	Rank   *string `gorm:"size:50" json:"rank,omitempty"`
+	Rating *int    `gorm:"index" json:"rating,omitempty"`
+	// Роли и позиции ("pos 4", "support", "AWP") - у каждой игры свои, поэтому свободные строки
+	Roles          []string `gorm:"type:jsonb;serializer:json" json:"roles,omitempty"`
+	Hours          *int     `json:"hours,omitempty"`
+	InGameNickname *string  `gorm:"size:64" json:"in_game_nickname,omitempty"`
+	ProfileURL     *string  `gorm:"size:255" json:"profile_url,omitempty"`
+	// Часы из библиотеки Steam - в отличие от Hours, их не может поправить сам игрок
+	VerifiedHours *int       `json:"verified_hours,omitempty"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *UserGameProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
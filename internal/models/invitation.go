@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending   InvitationStatus = "pending"
+	InvitationAccepted  InvitationStatus = "accepted"
+	InvitationDeclined  InvitationStatus = "declined"
+	InvitationCancelled InvitationStatus = "cancelled"
+)
+
+// ApplicationInvitation - приглашение автора заявки конкретному игроку
+type ApplicationInvitation struct {
+	ID            uuid.UUID        `gorm:"primaryKey" json:"id"`
+	ApplicationID uuid.UUID        `gorm:"not null;index" json:"application_id"`
+	Application   *GameApplication `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+	InviterID     uuid.UUID        `gorm:"not null;index" json:"inviter_id"` // Автор заявки
+	Inviter       *User            `gorm:"foreignKey:InviterID" json:"inviter,omitempty"`
+	InviteeID     uuid.UUID        `gorm:"not null;index" json:"invitee_id"` // Кого пригласили
+	Invitee       *User            `gorm:"foreignKey:InviteeID" json:"invitee,omitempty"`
+
+	Message *string          `gorm:"type:text" json:"message,omitempty"`
+	Status  InvitationStatus `gorm:"default:'pending';index" json:"status"`
+
+	// Отклик, созданный при принятии приглашения
+	ResponseID *uuid.UUID `json:"response_id,omitempty"`
+
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (i *ApplicationInvitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
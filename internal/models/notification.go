@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationType string
+
+const (
+	NotificationNewResponse         NotificationType = "new_response"
+	NotificationResponseAccepted    NotificationType = "response_accepted"
+	NotificationResponseRejected    NotificationType = "response_rejected"
+	NotificationNewMessage          NotificationType = "new_message"
+	NotificationApplicationExpiring NotificationType = "application_expiring"
+	NotificationReviewReceived      NotificationType = "review_received"
+)
+
+// Notification - событие для пользователя в центре уведомлений
+type Notification struct {
+	ID     uuid.UUID        `gorm:"primaryKey" json:"id"`
+	UserID uuid.UUID        `gorm:"not null;index:idx_notification_user_read" json:"user_id"`
+	Type   NotificationType `gorm:"not null;size:40" json:"type"`
+
+	// Кто вызвал событие (откликнувшийся, автор заявки, отправитель)
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+	Actor   *User      `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+
+	ApplicationID *uuid.UUID       `gorm:"index" json:"application_id,omitempty"`
+	Application   *GameApplication `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+	// Связанный объект: отклик, диалог или отзыв - в зависимости от типа
+	EntityID *uuid.UUID `gorm:"index" json:"entity_id,omitempty"`
+
+	Data map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"data,omitempty"`
+
+	ReadAt *time.Time `gorm:"index:idx_notification_user_read" json:"read_at,omitempty"`
+	// Когда уведомление обработала email-рассылка (отправлено, попало в дайджест или отключено)
+	EmailedAt *time.Time `gorm:"index" json:"-"`
+	// Когда уведомление отправлено в Telegram (или пропущено, если бот не привязан)
+	TelegramSentAt *time.Time `gorm:"index" json:"-"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// FieldVisibility - кому видно поле профиля
+type FieldVisibility string
+
+const (
+	VisibleEveryone  FieldVisibility = "everyone"
+	VisibleTeammates FieldVisibility = "teammates" // игроки, с которыми был в одном составе заявки
+	VisibleNobody    FieldVisibility = "nobody"
+)
+
+func (v FieldVisibility) IsValid() bool {
+	return v == VisibleEveryone || v == VisibleTeammates || v == VisibleNobody
+}
+
+// PrivacySettings - видимость контактов и личных данных для других пользователей.
+// Пустое значение - настройка по умолчанию из DefaultPrivacy
+type PrivacySettings struct {
+	Discord   FieldVisibility `json:"discord,omitempty"`
+	Telegram  FieldVisibility `json:"telegram,omitempty"`
+	Steam     FieldVisibility `json:"steam,omitempty"`
+	BirthDate FieldVisibility `json:"birth_date,omitempty"` // другим показывается возраст, а не дата
+	Gender    FieldVisibility `json:"gender,omitempty"`
+}
+
+// DefaultPrivacy - контакты видны только тиммейтам, остальное - всем
+var DefaultPrivacy = PrivacySettings{
+	Discord:   VisibleTeammates,
+	Telegram:  VisibleTeammates,
+	Steam:     VisibleEveryone,
+	BirthDate: VisibleEveryone,
+	Gender:    VisibleEveryone,
+}
+
+// WithDefaults заполняет незаданные поля значениями по умолчанию
+func (p PrivacySettings) WithDefaults() PrivacySettings {
+	fill := func(value *FieldVisibility, fallback FieldVisibility) {
+		if !value.IsValid() {
+			*value = fallback
+		}
+	}
+	fill(&p.Discord, DefaultPrivacy.Discord)
+	fill(&p.Telegram, DefaultPrivacy.Telegram)
+	fill(&p.Steam, DefaultPrivacy.Steam)
+	fill(&p.BirthDate, DefaultPrivacy.BirthDate)
+	fill(&p.Gender, DefaultPrivacy.Gender)
+	return p
+}
+
+// HasTeammateFields - есть ли поля, видимость которых зависит от того, тиммейт ли зритель
+func (p PrivacySettings) HasTeammateFields() bool {
+	p = p.WithDefaults()
+	for _, value := range []FieldVisibility{p.Discord, p.Telegram, p.Steam, p.BirthDate, p.Gender} {
+		if value == VisibleTeammates {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectivePrivacy - действующие настройки приватности пользователя
+func (u *User) EffectivePrivacy() PrivacySettings {
+	if u.Privacy == nil {
+		return DefaultPrivacy
+	}
+	return u.Privacy.WithDefaults()
+}
+
+// HidePrivateFields готовит профиль к показу другому пользователю: убирает email,
+// настройки приватности, дату рождения (остается возраст) и поля, которые владелец
+// не показывает этому зрителю
+func (u *User) HidePrivateFields(isTeammate bool) {
+	privacy := u.EffectivePrivacy()
+	visible := func(value FieldVisibility) bool {
+		return value == VisibleEveryone || value == VisibleTeammates && isTeammate
+	}
+
+	if !visible(privacy.Discord) {
+		u.Discord = nil
+	}
+	if !visible(privacy.Telegram) {
+		u.Telegram = nil
+	}
+	if !visible(privacy.Steam) {
+		u.SteamID = nil
+	}
+	if !visible(privacy.Gender) {
+		u.Gender = nil
+	}
+	if !visible(privacy.BirthDate) {
+		u.Age = nil
+	}
+	u.BirthDate = nil
+	u.Email = ""
+	u.Privacy = nil
+}
+
+// AgeAt - полных лет на момент now
+func AgeAt(birthDate time.Time, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || now.Month() == birthDate.Month() && now.Day() < birthDate.Day() {
+		age--
+	}
+	return age
+}
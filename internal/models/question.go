@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type QuestionType string
+
+const (
+	QuestionText           QuestionType = "text"
+	QuestionSingleChoice   QuestionType = "single_choice"
+	QuestionMultipleChoice QuestionType = "multiple_choice"
+	QuestionNumber         QuestionType = "number" // Например "сколько часов наиграно"
+)
+
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionText, QuestionSingleChoice, QuestionMultipleChoice, QuestionNumber:
+		return true
+	}
+	return false
+}
+
+func (t QuestionType) IsChoice() bool {
+	return t == QuestionSingleChoice || t == QuestionMultipleChoice
+}
+
+// ApplicationQuestion - вопрос автора заявки, на который нужно ответить при отклике
+type ApplicationQuestion struct {
+	ID            uuid.UUID    `gorm:"primaryKey" json:"id"`
+	ApplicationID uuid.UUID    `gorm:"not null;index" json:"application_id"`
+	Position      int          `gorm:"not null;default:0" json:"position"`
+	Type          QuestionType `gorm:"not null;size:20" json:"type"`
+	Prompt        string       `gorm:"not null" json:"prompt"`
+	Options       []string     `gorm:"type:jsonb;serializer:json" json:"options,omitempty"` // Варианты для choice-вопросов
+	Required      bool         `json:"required"`
+	MinValue      *float64     `json:"min_value,omitempty"` // Ограничения для number-вопросов
+	MaxValue      *float64     `json:"max_value,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (q *ApplicationQuestion) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == uuid.Nil {
+		q.ID = uuid.New()
+	}
+	return nil
+}
+
+// HasOption проверяет, что вариант ответа есть среди вариантов вопроса
+func (q *ApplicationQuestion) HasOption(value string) bool {
+	for _, option := range q.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseAnswer - ответ откликнувшегося на вопрос заявки.
+// Заполняется одно поле в зависимости от типа вопроса
+type ResponseAnswer struct {
+	ID         uuid.UUID `gorm:"primaryKey" json:"id"`
+	ResponseID uuid.UUID `gorm:"not null;uniqueIndex:idx_response_question" json:"response_id"`
+	QuestionID uuid.UUID `gorm:"not null;uniqueIndex:idx_response_question;index" json:"question_id"`
+
+	Text    *string  `gorm:"type:text" json:"text,omitempty"`
+	Choices []string `gorm:"type:jsonb;serializer:json" json:"choices,omitempty"`
+	Number  *float64 `json:"number,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (a *ResponseAnswer) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetApplication ReportTargetType = "application"
+	ReportTargetUser        ReportTargetType = "user"
+	ReportTargetMessage     ReportTargetType = "message"
+	// Жалобы на отклики создает только автофильтр
+	ReportTargetResponse ReportTargetType = "response"
+)
+
+// IsValid - объекты, на которые могут жаловаться пользователи
+func (t ReportTargetType) IsValid() bool {
+	switch t {
+	case ReportTargetApplication, ReportTargetUser, ReportTargetMessage:
+		return true
+	}
+	return false
+}
+
+type ReportReason string
+
+const (
+	ReasonSpam        ReportReason = "spam"
+	ReasonOffensive   ReportReason = "offensive"
+	ReasonHarassment  ReportReason = "harassment"
+	ReasonBadNickname ReportReason = "inappropriate_nickname"
+	ReasonScam        ReportReason = "scam"
+	ReasonOther       ReportReason = "other"
+	// Выставляется автофильтром контента, пользователям недоступна
+	ReasonAutoFlagged ReportReason = "auto_flagged"
+)
+
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReasonSpam, ReasonOffensive, ReasonHarassment, ReasonBadNickname, ReasonScam, ReasonOther:
+		return true
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportActioned  ReportStatus = "actioned"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// Report - жалоба пользователя на заявку, профиль или сообщение.
+// Без ReporterID - жалоба от автофильтра контента
+type Report struct {
+	ID         uuid.UUID        `gorm:"primaryKey" json:"id"`
+	ReporterID *uuid.UUID       `gorm:"index" json:"reporter_id,omitempty"`
+	Reporter   *User            `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+	TargetType ReportTargetType `gorm:"not null;size:20;index:idx_report_target" json:"target_type"`
+	TargetID   uuid.UUID        `gorm:"not null;index:idx_report_target" json:"target_id"`
+	// Владелец контента - кого касаются warn/suspend
+	TargetUserID uuid.UUID `gorm:"not null;index" json:"target_user_id"`
+
+	ReasonCode ReportReason `gorm:"not null;size:30" json:"reason_code"`
+	Details    *string      `gorm:"type:text" json:"details,omitempty"`
+	// Копия контента на момент жалобы - сообщение могут удалить, заявку изменить
+	TargetSnapshot string `gorm:"type:text" json:"target_snapshot"`
+
+	Status       ReportStatus `gorm:"default:'open';index" json:"status"`
+	ResolvedByID *uuid.UUID   `json:"resolved_by_id,omitempty"`
+	ResolvedAt   *time.Time   `json:"resolved_at,omitempty"`
+
+	Actions []ModerationAction `gorm:"foreignKey:ReportID" json:"actions,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (r *Report) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type ModerationActionType string
+
+const (
+	ActionHideApplication ModerationActionType = "hide_application"
+	ActionDeleteMessage   ModerationActionType = "delete_message"
+	ActionWarn            ModerationActionType = "warn"
+	ActionSuspend         ModerationActionType = "suspend"
+	ActionBan             ModerationActionType = "ban"
+	ActionUnsuspend       ModerationActionType = "unsuspend"
+	ActionDismiss         ModerationActionType = "dismiss"
+)
+
+// IsValid - действия, доступные при разборе жалобы (unsuspend выполняется отдельно)
+func (a ModerationActionType) IsValid() bool {
+	switch a {
+	case ActionHideApplication, ActionDeleteMessage, ActionWarn, ActionSuspend, ActionBan, ActionDismiss:
+		return true
+	}
+	return false
+}
+
+// ModerationAction - запись журнала модерации: кто, что и над чем сделал
+type ModerationAction struct {
+	ID           uuid.UUID            `gorm:"primaryKey" json:"id"`
+	ReportID     *uuid.UUID           `gorm:"index" json:"report_id,omitempty"`
+	ModeratorID  uuid.UUID            `gorm:"not null;index" json:"moderator_id"`
+	Moderator    *User                `gorm:"foreignKey:ModeratorID" json:"moderator,omitempty"`
+	Action       ModerationActionType `gorm:"not null;size:30" json:"action"`
+	TargetType   ReportTargetType     `gorm:"not null;size:20;index:idx_action_target" json:"target_type"`
+	TargetID     uuid.UUID            `gorm:"not null;index:idx_action_target" json:"target_id"`
+	TargetUserID uuid.UUID            `gorm:"not null;index" json:"target_user_id"`
+	Note         *string              `gorm:"type:text" json:"note,omitempty"`
+	// Для suspend - до какого момента
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (a *ModerationAction) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
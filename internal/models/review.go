@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReviewType string
+
+const (
+	ReviewLike    ReviewType = "like"
+	ReviewDislike ReviewType = "dislike"
+)
+
+func (t ReviewType) IsValid() bool {
+	return t == ReviewLike || t == ReviewDislike
+}
+
+// Review - отзыв об игроке, с которым были в одном составе заявки.
+// Один отзыв на пару игроков в рамках одной заявки
+type Review struct {
+	ID             uuid.UUID        `gorm:"primaryKey" json:"id"`
+	ReviewerID     uuid.UUID        `gorm:"not null;uniqueIndex:idx_review_pair" json:"reviewer_id"`
+	Reviewer       *User            `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+	ReviewedUserID uuid.UUID        `gorm:"not null;uniqueIndex:idx_review_pair;index" json:"reviewed_user_id"`
+	ReviewedUser   *User            `gorm:"foreignKey:ReviewedUserID" json:"reviewed_user,omitempty"`
+	ApplicationID  uuid.UUID        `gorm:"not null;uniqueIndex:idx_review_pair" json:"application_id"`
+	Application    *GameApplication `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+
+	ReviewType ReviewType `gorm:"not null;size:10" json:"review_type"`
+	Comment    *string    `gorm:"type:text" json:"comment,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (r *Review) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsEditable - отзыв можно менять только в течение окна после создания
+func (r *Review) IsEditable(window time.Duration) bool {
+	return time.Now().Before(r.CreatedAt.Add(window))
+}
+
+// CounterColumn - какой счетчик пользователя соответствует типу отзыва
+func (t ReviewType) CounterColumn() string {
+	if t == ReviewDislike {
+		return "dislikes_count"
+	}
+	return "likes_count"
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SteamOwnedGame - игра из библиотеки Steam и время в ней за все время
+type SteamOwnedGame struct {
+	AppID           int    `json:"app_id"`
+	Name            string `json:"name"`
+	PlaytimeMinutes int    `json:"playtime_minutes"`
+}
+
+// SteamProfile - закешированные публичные данные привязанного профиля Steam.
+// Обновляются не чаще STEAM_CACHE_TTL, чтобы не упираться в лимиты Steam Web API
+type SteamProfile struct {
+	UserID      uuid.UUID `gorm:"primaryKey" json:"user_id"`
+	SteamID     string    `gorm:"size:20;not null;index" json:"steam_id"`
+	PersonaName string    `gorm:"size:64" json:"persona_name"`
+	ProfileURL  string    `gorm:"size:255" json:"profile_url"`
+	AvatarURL   string    `gorm:"size:255" json:"avatar_url"`
+	// Steam отдает библиотеку только у профилей с открытыми "Игровыми данными"
+	GamesVisible bool             `json:"games_visible"`
+	Games        []SteamOwnedGame `gorm:"type:jsonb;serializer:json" json:"games"`
+	FetchedAt    time.Time        `json:"fetched_at"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Playtime - наигранные минуты в игре Steam или false, если игры нет в библиотеке
+func (p *SteamProfile) Playtime(appID int) (int, bool) {
+	for _, game := range p.Games {
+		if game.AppID == appID {
+			return game.PlaytimeMinutes, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TelegramLinkCode - одноразовый код привязки Telegram к аккаунту.
+// Пользователь получает его на сайте и отправляет боту командой /start
+type TelegramLinkCode struct {
+	ID        uuid.UUID  `gorm:"primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"not null;index" json:"user_id"`
+	Code      string     `gorm:"not null;uniqueIndex;size:64" json:"-"` // SHA256 hash
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (t *TelegramLinkCode) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *TelegramLinkCode) IsValid() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
+
+// TelegramNotificationTypes - уведомления, которые дублируются в Telegram
+var TelegramNotificationTypes = []NotificationType{
+	NotificationNewResponse,
+	NotificationResponseAccepted,
+	NotificationResponseRejected,
+	NotificationNewMessage,
+}
@@ -1,11 +1,32 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// CanModerate - модераторы и админы разбирают жалобы
+func (r Role) CanModerate() bool {
+	return r == RoleModerator || r == RoleAdmin
+}
+
+// CanSuspend - может ли пользователь с этой ролью блокировать пользователя с ролью target.
+// Модераторов и админов блокирует только админ
+func (r Role) CanSuspend(target Role) bool {
+	return r.CanModerate() && (!target.CanModerate() || r == RoleAdmin)
+}
+
 type User struct {
 	ID            uuid.UUID `gorm:"primaryKey" json:"id"`
 	Email         string    `gorm:"not null;uniqueIndex" json:"email"`
@@ -22,8 +43,61 @@ type User struct {
 	Languages     []string  `gorm:"type:jsonb;serializer:json" json:"languages,omitempty"` // Языки, которыми владеет пользователь
 	LikesCount    int       `gorm:"default:0" json:"likes_count"`
 	DislikesCount int       `gorm:"default:0" json:"dislikes_count"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	// Отметки посещаемости от тиммейтов и вычисляемая надежность в процентах
+	AttendedCount int      `gorm:"default:0" json:"attended_count"`
+	NoShowCount   int      `gorm:"default:0" json:"no_show_count"`
+	Reliability   *float64 `gorm:"-" json:"reliability,omitempty"`
+	Role          Role     `gorm:"size:20;default:'user'" json:"role"`
+	// Язык писем и сообщений API (ru, en)
+	Locale string `gorm:"size:5;default:'ru'" json:"locale"`
+	// Блокировка, выставленная модератором: временная до SuspendedUntil или бессрочная с BannedAt
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	BannedAt         *time.Time `json:"banned_at,omitempty"`
+	SuspensionReason *string    `gorm:"type:text" json:"-"`
+	// Когда пользователю последний раз отправляли дайджест уведомлений
+	LastDigestAt *time.Time `json:"-"`
+	// Миниатюры аватара по стороне в пикселях ("512", "64"); avatar_url - самая большая
+	AvatarThumbnails map[string]string `gorm:"type:jsonb;serializer:json" json:"avatar_thumbnails,omitempty"`
+	// Ключи файлов аватара в хранилище - по ним очистка находит файлы без ссылок
+	AvatarKeys []string `gorm:"type:jsonb;serializer:json" json:"-"`
+	// Игровые профили по играм, загружаются через Preload
+	GameProfiles []UserGameProfile `gorm:"foreignKey:UserID" json:"game_profiles,omitempty"`
+	// Привязанный аккаунт Steam (64-битный SteamID) и время привязки
+	SteamID       *string    `gorm:"size:20;uniqueIndex" json:"steam_id,omitempty"`
+	SteamLinkedAt *time.Time `json:"-"`
+	// Кому видны контакты и личные данные (nil - DefaultPrivacy) и возраст по дате рождения
+	Privacy *PrivacySettings `gorm:"type:jsonb;serializer:json" json:"privacy,omitempty"`
+	Age     *int             `gorm:"-" json:"age,omitempty"`
+	// Привязанный чат с Telegram-ботом и время привязки
+	TelegramChatID   *int64     `gorm:"uniqueIndex" json:"-"`
+	TelegramLinkedAt *time.Time `json:"-"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AfterFind считает надежность, если у пользователя есть отметки посещаемости, и возраст
+func (u *User) AfterFind(tx *gorm.DB) error {
+	u.Reliability = ReliabilityPercent(u.AttendedCount, u.NoShowCount)
+	if u.BirthDate != nil && !u.BirthDate.IsZero() {
+		age := AgeAt(u.BirthDate.Time, time.Now())
+		u.Age = &age
+	}
+	return nil
+}
+
+// IsSuspended - действует ли на момент now временная или бессрочная блокировка
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.BannedAt != nil || u.SuspendedUntil != nil && now.Before(*u.SuspendedUntil)
+}
+
+// ReliabilityPercent - доля игр, на которые игрок пришел; nil если отметок нет
+func ReliabilityPercent(attended, noShow int) *float64 {
+	total := attended + noShow
+	if total == 0 {
+		return nil
+	}
+	percent := math.Round(float64(attended)*1000/float64(total)) / 10
+	return &percent
 }
 
 type AuthRequest struct {
@@ -0,0 +1,23 @@
+package models
+
+import "testing"
+
+func TestRoleCanSuspend(t *testing.T) {
+	cases := []struct {
+		actor, target Role
+		want          bool
+	}{
+		{RoleModerator, RoleUser, true},
+		{RoleModerator, RoleModerator, false},
+		{RoleModerator, RoleAdmin, false},
+		{RoleAdmin, RoleUser, true},
+		{RoleAdmin, RoleModerator, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleUser, RoleUser, false},
+	}
+	for _, tc := range cases {
+		if got := tc.actor.CanSuspend(tc.target); got != tc.want {
+			t.Errorf("%s.CanSuspend(%s) = %v, want %v", tc.actor, tc.target, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookApplicationCreated WebhookEvent = "application.created"
+	WebhookApplicationUpdated WebhookEvent = "application.updated"
+	WebhookApplicationClosed  WebhookEvent = "application.closed" // удалена, заполнена, истекла или убрана из ленты
+	WebhookResponseCreated    WebhookEvent = "response.created"
+	WebhookResponseAccepted   WebhookEvent = "response.accepted"
+	WebhookPing               WebhookEvent = "ping" // тестовая доставка, на нее не подписываются
+)
+
+// WebhookEvents - события, на которые можно подписаться
+var WebhookEvents = []WebhookEvent{
+	WebhookApplicationCreated,
+	WebhookApplicationUpdated,
+	WebhookApplicationClosed,
+	WebhookResponseCreated,
+	WebhookResponseAccepted,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook - адрес, на который отправляются подписанные HMAC события.
+// Вебхуки пользователей получают события публичных заявок и своих заявок,
+// вебхуки админов - все события
+type Webhook struct {
+	ID      uuid.UUID `gorm:"primaryKey" json:"id"`
+	OwnerID uuid.UUID `gorm:"not null;index" json:"owner_id"`
+	URL     string    `gorm:"not null" json:"url"`
+	// Ключ подписи, показывается только при создании
+	Secret      string         `gorm:"size:64;not null" json:"-"`
+	Events      []WebhookEvent `gorm:"type:jsonb;serializer:json" json:"events"`
+	GameID      *uuid.UUID     `gorm:"index" json:"game_id,omitempty"` // только заявки этой игры
+	Description string         `gorm:"size:200" json:"description"`
+	IsActive    bool           `gorm:"index" json:"is_active"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// Subscribed - подписан ли вебхук на событие
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead" // исчерпаны попытки доставки
+)
+
+// WebhookDelivery - журнал доставки события на вебхук. Пишется в той же транзакции,
+// что и изменение, и отправляется фоновым воркером с повторами
+type WebhookDelivery struct {
+	ID            uuid.UUID       `gorm:"primaryKey" json:"id"`
+	WebhookID     uuid.UUID       `gorm:"not null;index" json:"webhook_id"`
+	Event         WebhookEvent    `gorm:"size:40;not null" json:"event"`
+	ApplicationID *uuid.UUID      `gorm:"index" json:"application_id,omitempty"`
+	Payload       json.RawMessage `gorm:"type:jsonb;serializer:json" json:"payload"`
+
+	Status         WebhookDeliveryStatus `gorm:"size:20;default:'pending';index:idx_webhook_delivery_due" json:"status"`
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"index:idx_webhook_delivery_due" json:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	ResponseBody   *string               `gorm:"type:text" json:"response_body,omitempty"`
+	LastError      *string               `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	return nil
+}
@@ -7,10 +7,19 @@ import (
 )
 
 func SetupRoutes(app *fiber.App) {
+	// Обновления Telegram-бота регистрируются до общего rate limiter:
+	// все запросы приходят с нескольких адресов Telegram
+	app.Post("/api/telegram/webhook", handlers.TelegramWebhook)
+
+	// Загруженные файлы (аватары) из хранилища
+	app.Get("/media/*", handlers.ServeMedia)
+
 	api := app.Group("/api")
 
 	// Общий rate limiter для всех API запросов (100 req/min)
 	api.Use(middleware.APIRateLimiter())
+	// Пользователь по токену; токены заблокированных пользователей не принимаются
+	api.Use(middleware.Identify)
 
 	//auth
 	auth := api.Group("/auth")
@@ -21,16 +30,44 @@ func SetupRoutes(app *fiber.App) {
 	auth.Get("/me", handlers.GetMe)
 	auth.Get("/ws-token", handlers.GetWebSocketToken) // Токен для WebSocket
 	auth.Patch("/me", handlers.UpdateProfile)
+	auth.Post("/me/avatar", middleware.AuthRequired, handlers.UploadAvatar)
+	auth.Delete("/me/avatar", middleware.AuthRequired, handlers.DeleteAvatar)
 	// Password reset endpoints
 	auth.Post("/forgot-password", middleware.AuthRateLimiter(), middleware.RecaptchaMiddleware(), handlers.ForgotPassword)
 	auth.Post("/reset-password", middleware.AuthRateLimiter(), handlers.ResetPassword)
+	// Email notification preferences
+	auth.Get("/me/email-preferences", middleware.AuthRequired, handlers.GetEmailPreferences)
+	auth.Put("/me/email-preferences", middleware.AuthRequired, handlers.UpdateEmailPreferences)
+	// Видимость контактов и личных данных для других пользователей
+	auth.Get("/me/privacy", middleware.AuthRequired, handlers.GetPrivacy)
+	auth.Put("/me/privacy", middleware.AuthRequired, handlers.UpdatePrivacy)
+
+	// Отписка по ссылке из письма
+	api.Post("/email/unsubscribe", handlers.UnsubscribeEmail)
 
 	//users
 	users := api.Group("/users", middleware.AuthRequired)
 	users.Get("/:id", handlers.GetUserByID)
 	users.Get("/:id/applications", handlers.GetApplicationsByUserID)
+	users.Get("/:id/reviews", handlers.GetUserReviews)
+	users.Get("/:id/game-profiles", handlers.GetUserGameProfiles)
 	users.Patch("/:id", handlers.UpdateProfile)
 
+	// Игровые профили текущего пользователя (ранг, роли, часы в конкретной игре)
+	gameProfiles := api.Group("/game-profiles", middleware.AuthRequired)
+	gameProfiles.Get("/", handlers.GetMyGameProfiles)
+	gameProfiles.Post("/", handlers.CreateGameProfile)
+	gameProfiles.Patch("/:id", handlers.UpdateGameProfile)
+	gameProfiles.Delete("/:id", handlers.DeleteGameProfile)
+
+	// Привязка Steam: callback открывает браузер при возврате из Steam, поэтому он без авторизации
+	steam := api.Group("/steam")
+	steam.Get("/callback", handlers.SteamCallback)
+	steam.Get("/", middleware.AuthRequired, handlers.GetSteam)
+	steam.Post("/link", middleware.AuthRequired, handlers.StartSteamLink)
+	steam.Delete("/link", middleware.AuthRequired, handlers.UnlinkSteam)
+	steam.Post("/refresh", middleware.AuthRequired, handlers.RefreshSteam)
+
 	//countries
 	countries := api.Group("/countries")
 	countries.Get("/", handlers.GetAllCountries)
@@ -46,19 +83,96 @@ func SetupRoutes(app *fiber.App) {
 	applications := api.Group("/applications")
 	applications.Get("/", handlers.GetAllApplications)
 	applications.Get("/my", middleware.AuthRequired, handlers.GetUserApplications)
+	applications.Get("/shared/:code", handlers.GetApplicationByShareCode)
 	applications.Get("/:id", handlers.GetApplicationByID)
 	applications.Post("/", middleware.AuthRequired, middleware.CreateApplicationRateLimiter(), handlers.CreateGameApplication)
 	applications.Patch("/:id", middleware.AuthRequired, handlers.UpdateApplication)
 	applications.Delete("/:id", middleware.AuthRequired, handlers.DeleteApplication)
+	applications.Post("/:id/share-code", middleware.AuthRequired, handlers.GetApplicationShareCode)
+	applications.Delete("/:id/share-code", middleware.AuthRequired, handlers.RevokeApplicationShareCode)
 
 	// Application responses
 	applications.Post("/:id/responses", middleware.AuthRequired, handlers.CreateApplicationResponse)
 	applications.Get("/:id/responses", middleware.AuthRequired, handlers.GetApplicationResponses)
+	applications.Post("/:id/responses/bulk", middleware.AuthRequired, handlers.BulkUpdateResponseStatus)
+
+	// Attendance
+	applications.Post("/:id/attendance", middleware.AuthRequired, handlers.ReportAttendance)
+	applications.Get("/:id/attendance", middleware.AuthRequired, handlers.GetApplicationAttendance)
+
+	// Application invitations
+	applications.Post("/:id/invitations", middleware.AuthRequired, handlers.CreateInvitation)
+	applications.Get("/:id/invitations", middleware.AuthRequired, handlers.GetApplicationInvitations)
 
 	//responses
 	responses := api.Group("/responses")
 	responses.Get("/my", middleware.AuthRequired, handlers.GetMyResponses)
 	responses.Patch("/:id", middleware.AuthRequired, handlers.UpdateResponseStatus)
+	responses.Post("/:id/withdraw", middleware.AuthRequired, handlers.WithdrawApplicationResponse)
+	responses.Patch("/:id/triage", middleware.AuthRequired, handlers.UpdateResponseTriage)
+
+	//blocks
+	blocks := api.Group("/blocks", middleware.AuthRequired)
+	blocks.Get("/", handlers.GetMyBlocks)
+	blocks.Post("/", handlers.BlockUser)
+	blocks.Delete("/:userId", handlers.UnblockUser)
+
+	//reports
+	api.Post("/reports", middleware.AuthRequired, handlers.CreateReport)
+
+	//moderation
+	moderation := api.Group("/moderation", middleware.AuthRequired, middleware.ModeratorRequired)
+	moderation.Get("/reports", handlers.GetModerationReports)
+	moderation.Get("/reports/:id", handlers.GetModerationReport)
+	moderation.Post("/reports/:id/actions", handlers.TakeModerationAction)
+	moderation.Get("/actions", handlers.GetModerationActions)
+	moderation.Post("/users/:id/suspension", handlers.SuspendUser)
+	moderation.Delete("/users/:id/suspension", handlers.UnsuspendUser)
+
+	//admin
+	admin := api.Group("/admin", middleware.AuthRequired, middleware.AdminRequired)
+	admin.Get("/emails", handlers.GetEmailOutbox)
+	admin.Post("/emails/:id/retry", handlers.RetryEmail)
+	admin.Get("/emails/preview/:name", handlers.PreviewEmail)
+	admin.Get("/discord-channels", handlers.GetDiscordChannels)
+	admin.Put("/games/:id/discord", handlers.SetGameDiscordChannel)
+	admin.Delete("/games/:id/discord", handlers.DeleteGameDiscordChannel)
+
+	//reviews
+	reviews := api.Group("/reviews", middleware.AuthRequired)
+	reviews.Post("/", handlers.CreateReview)
+	reviews.Patch("/:id", handlers.UpdateReview)
+	reviews.Delete("/:id", handlers.DeleteReview)
+
+	//invitations
+	invitations := api.Group("/invitations", middleware.AuthRequired)
+	invitations.Get("/my", handlers.GetMyInvitations)
+	invitations.Post("/:id/accept", handlers.AcceptInvitation)
+	invitations.Post("/:id/decline", handlers.DeclineInvitation)
+	invitations.Delete("/:id", handlers.CancelInvitation)
+
+	//notifications
+	notifications := api.Group("/notifications", middleware.AuthRequired)
+	notifications.Get("/", handlers.GetNotifications)
+	notifications.Get("/unread-count", handlers.GetUnreadNotificationsCount)
+	notifications.Post("/read-all", handlers.MarkAllNotificationsRead)
+	notifications.Post("/:id/read", handlers.MarkNotificationRead)
+
+	//webhooks
+	hooks := api.Group("/webhooks", middleware.AuthRequired)
+	hooks.Get("/", handlers.GetWebhooks)
+	hooks.Post("/", handlers.CreateWebhook)
+	hooks.Patch("/:id", handlers.UpdateWebhook)
+	hooks.Delete("/:id", handlers.DeleteWebhook)
+	hooks.Get("/:id/deliveries", handlers.GetWebhookDeliveries)
+	hooks.Post("/:id/deliveries/:deliveryId/retry", handlers.RetryWebhookDelivery)
+	hooks.Post("/:id/ping", handlers.PingWebhook)
+
+	//telegram
+	tg := api.Group("/telegram", middleware.AuthRequired)
+	tg.Get("/link", handlers.GetTelegramLink)
+	tg.Post("/link", handlers.CreateTelegramLinkCode)
+	tg.Delete("/link", handlers.DeleteTelegramLink)
 
 	// Conversations & Messages
 	conversations := api.Group("/conversations", middleware.AuthRequired)
@@ -67,4 +181,5 @@ func SetupRoutes(app *fiber.App) {
 	conversations.Get("/:id", handlers.GetConversationByID)              // Get specific conversation
 	conversations.Get("/:id/messages", handlers.GetConversationMessages) // Get messages with pagination
 	conversations.Patch("/:id/read", handlers.MarkMessagesAsRead)        // Mark all messages as read
+	conversations.Post("/:id/messages", handlers.SendMessage)            // Send a message
 }
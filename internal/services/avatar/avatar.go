@@ -0,0 +1,112 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // декодеры форматов регистрируются при импорте
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	"github.com/duker221/teamly/internal/config"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+	ErrTooSmall          = errors.New("image is too small")
+)
+
+// MinSide - минимальная сторона исходного изображения в пикселях
+const MinSide = 64
+
+// AllowedTypes - форматы, которые принимаются на загрузку. Тип определяется
+// по содержимому файла, а не по имени или заголовку Content-Type
+var AllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// ContentType - формат миниатюр
+const ContentType = "image/jpeg"
+
+// Thumbnail - квадратная миниатюра стороной Size
+type Thumbnail struct {
+	Size int
+	Data []byte
+}
+
+// Process проверяет загруженный файл и делает из него миниатюры всех размеров
+// из AVATAR_SIZES: центральный квадрат, поворот по EXIF, JPEG.
+// Перекодирование отбрасывает EXIF и прочие метаданные, включая геолокацию
+func Process(data []byte) ([]Thumbnail, error) {
+	if !isAllowed(http.DetectContentType(data)) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Размеры читаем из заголовка до декодирования, чтобы не распаковывать "бомбы"
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if header.Width*header.Height > config.AvatarConfig.MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	if header.Width < MinSide || header.Height < MinSide {
+		return nil, ErrTooSmall
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	orientation := jpegOrientation(data)
+	source := square(img)
+	side := source.Bounds().Dx()
+
+	thumbnails := make([]Thumbnail, 0, len(config.AvatarConfig.Sizes))
+	for _, size := range config.AvatarConfig.Sizes {
+		// Маленькие исходники не растягиваем
+		pixels := min(size, side)
+		dst := image.NewRGBA(image.Rect(0, 0, pixels, pixels))
+		// Прозрачные области PNG/GIF заливаем белым - в JPEG нет альфа-канала
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, dst.Bounds(), source, source.Bounds(), draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, orient(dst, orientation), &jpeg.Options{Quality: config.AvatarConfig.JPEGQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, Thumbnail{Size: size, Data: buf.Bytes()})
+	}
+	return thumbnails, nil
+}
+
+func isAllowed(contentType string) bool {
+	for _, allowed := range AllowedTypes {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// square вырезает центральный квадрат изображения. Поворот по EXIF применяется
+// уже к миниатюре: центральный квадрат при повороте остается тем же
+func square(img image.Image) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	rect := image.Rect(x, y, x+side, y+side)
+
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,59 @@
+package contentfilter
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	urlPattern    = regexp.MustCompile(`(?i)\b(?:https?://|www\.)([^\s/]+)\S*`)
+	domainPattern = regexp.MustCompile(`(?i)\b((?:[a-z0-9-]+\.)+(?:com|net|org|ru|su|io|gg|me|xyz|info|ly|to|рф))\b`)
+	emailPattern  = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	phonePattern  = regexp.MustCompile(`(?:\+\d|\b\d)(?:[\s\-()]*\d){9,}`)
+)
+
+// ContactsCheck находит ссылки, email и телефоны в тексте.
+// Для связи в профиле есть отдельные поля Discord/Telegram
+type ContactsCheck struct {
+	allowed []string
+}
+
+func NewContactsCheck(allowedDomains []string) *ContactsCheck {
+	return &ContactsCheck{allowed: allowedDomains}
+}
+
+func (c *ContactsCheck) Name() string { return "contacts" }
+
+func (c *ContactsCheck) Inspect(in Input) string {
+	if emailPattern.MatchString(in.Text) {
+		return "text contains an email address"
+	}
+	// Email уже проверен, чтобы домен из адреса не считался ссылкой повторно
+	text := emailPattern.ReplaceAllString(in.Text, "")
+
+	for _, match := range urlPattern.FindAllStringSubmatch(text, -1) {
+		if !c.isAllowed(match[1]) {
+			return "text contains a link"
+		}
+	}
+	for _, match := range domainPattern.FindAllStringSubmatch(text, -1) {
+		if !c.isAllowed(match[1]) {
+			return "text contains a link"
+		}
+	}
+	if phonePattern.MatchString(text) {
+		return "text contains a phone number"
+	}
+	return ""
+}
+
+// isAllowed - домен из белого списка или его поддомен
+func (c *ContactsCheck) isAllowed(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for _, domain := range c.allowed {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
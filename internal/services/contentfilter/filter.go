@@ -0,0 +1,136 @@
+package contentfilter
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/duker221/teamly/internal/config"
+)
+
+// Action - что делать с контентом, на котором сработала проверка
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionFlag   Action = "flag"   // сохранить и отправить на модерацию
+	ActionReject Action = "reject" // не сохранять
+)
+
+// Input - проверяемый текст и предыдущие публикации автора для сравнения
+type Input struct {
+	Text     string
+	Previous []string
+}
+
+// Check - одна проверка конвейера. Возвращает причину срабатывания или ""
+type Check interface {
+	Name() string
+	Inspect(in Input) string
+}
+
+// Rule связывает проверку с действием при ее срабатывании
+type Rule struct {
+	Check  Check
+	Action Action
+}
+
+// Result - итог прогона: самое строгое действие и что его вызвало
+type Result struct {
+	Action Action `json:"action"`
+	Check  string `json:"check,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Pipeline прогоняет текст через все правила по порядку
+type Pipeline struct {
+	rules []Rule
+}
+
+func New(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Run останавливается на первом reject; иначе возвращает первый flag
+func (p *Pipeline) Run(in Input) Result {
+	result := Result{Action: ActionAllow}
+	if p == nil {
+		return result
+	}
+
+	for _, rule := range p.rules {
+		reason := rule.Check.Inspect(in)
+		if reason == "" {
+			continue
+		}
+		if rule.Action == ActionReject {
+			return Result{Action: ActionReject, Check: rule.Check.Name(), Reason: reason}
+		}
+		if result.Action == ActionAllow {
+			result = Result{Action: ActionFlag, Check: rule.Check.Name(), Reason: reason}
+		}
+	}
+	return result
+}
+
+var pipeline *Pipeline
+
+// Init собирает конвейер из config.ContentFilterConfig
+func Init() error {
+	cfg := config.ContentFilterConfig
+	if !cfg.Enabled {
+		log.Println("Content filter disabled")
+		pipeline = nil
+		return nil
+	}
+
+	ru, err := loadWordlist(cfg.WordlistRU, "wordlists/ru.txt")
+	if err != nil {
+		return err
+	}
+	en, err := loadWordlist(cfg.WordlistEN, "wordlists/en.txt")
+	if err != nil {
+		return err
+	}
+
+	var rules []Rule
+	add := func(check Check, action string) {
+		switch Action(action) {
+		case ActionReject, ActionFlag:
+			rules = append(rules, Rule{Check: check, Action: Action(action)})
+		case "off":
+		default:
+			log.Printf("Warning: unknown content filter action %q for %s, check disabled", action, check.Name())
+		}
+	}
+
+	add(NewWordlistCheck(ru, en), cfg.ProfanityAction)
+	add(NewContactsCheck(cfg.AllowedDomains), cfg.ContactsAction)
+	add(NewSimilarityCheck(cfg.SimilarityThreshold), cfg.SimilarityAction)
+
+	pipeline = New(rules...)
+	log.Printf("Content filter initialized with %d checks", len(rules))
+	return nil
+}
+
+// Run прогоняет текст через конвейер, собранный в Init
+func Run(in Input) Result {
+	return pipeline.Run(in)
+}
+
+// loadWordlist читает список из файла или встроенный по умолчанию
+func loadWordlist(path, embedded string) ([]string, error) {
+	if path == "" {
+		data, err := defaultWordlists.ReadFile(embedded)
+		if err != nil {
+			return nil, err
+		}
+		return parseWordlist(string(data)), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wordlist %s: %w", path, err)
+	}
+	return parseWordlist(string(data)), nil
+}
@@ -0,0 +1,59 @@
+package contentfilter
+
+// SimilarityCheck ловит повторные публикации: текст почти совпадает
+// с одной из недавних публикаций автора (коэффициент Жаккара по триграммам слов)
+type SimilarityCheck struct {
+	threshold float64
+}
+
+func NewSimilarityCheck(threshold float64) *SimilarityCheck {
+	return &SimilarityCheck{threshold: threshold}
+}
+
+func (s *SimilarityCheck) Name() string { return "similarity" }
+
+func (s *SimilarityCheck) Inspect(in Input) string {
+	if len(in.Previous) == 0 {
+		return ""
+	}
+
+	current := shingles(in.Text)
+	if len(current) == 0 {
+		return ""
+	}
+	for _, previous := range in.Previous {
+		if jaccard(current, shingles(previous)) >= s.threshold {
+			return "text duplicates one of your recent posts"
+		}
+	}
+	return ""
+}
+
+// shingles - множество триграмм слов; короткие тексты сравниваются по словам
+func shingles(text string) map[string]bool {
+	tokens := tokenize(text)
+	set := make(map[string]bool)
+	if len(tokens) < 3 {
+		for _, token := range tokens {
+			set[token] = true
+		}
+		return set
+	}
+	for i := 0; i+3 <= len(tokens); i++ {
+		set[tokens[i]+" "+tokens[i+1]+" "+tokens[i+2]] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for key := range a {
+		if b[key] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
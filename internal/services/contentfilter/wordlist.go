@@ -0,0 +1,102 @@
+package contentfilter
+
+import (
+	"embed"
+	"strings"
+	"unicode"
+)
+
+//go:embed wordlists/*.txt
+var defaultWordlists embed.FS
+
+// parseWordlist - по слову на строку, пустые строки и строки с # пропускаются
+func parseWordlist(data string) []string {
+	var words []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+// Латинские буквы и цифры, которыми подменяют кириллицу, чтобы обойти фильтр
+var cyrillicLookalikes = strings.NewReplacer(
+	"a", "а", "b", "в", "c", "с", "e", "е", "h", "н", "k", "к", "m", "м",
+	"o", "о", "p", "р", "t", "т", "x", "х", "y", "у", "z", "з", "3", "з", "0", "о", "6", "б",
+)
+
+// WordlistCheck ищет запрещенные слова. Слово со * на конце - корень,
+// совпадающий с началом слова в тексте, без * - только целое слово
+type WordlistCheck struct {
+	ru matcher
+	en matcher
+}
+
+type matcher struct {
+	words    map[string]bool
+	prefixes []string
+}
+
+func newMatcher(list []string) matcher {
+	m := matcher{words: make(map[string]bool)}
+	for _, word := range list {
+		if root, ok := strings.CutSuffix(word, "*"); ok {
+			m.prefixes = append(m.prefixes, root)
+		} else {
+			m.words[word] = true
+		}
+	}
+	return m
+}
+
+func (m matcher) match(token string) bool {
+	if m.words[token] {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(token, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func NewWordlistCheck(ru, en []string) *WordlistCheck {
+	return &WordlistCheck{ru: newMatcher(ru), en: newMatcher(en)}
+}
+
+func (w *WordlistCheck) Name() string { return "profanity" }
+
+func (w *WordlistCheck) Inspect(in Input) string {
+	for _, token := range tokenize(in.Text) {
+		if w.en.match(token) {
+			return "text contains prohibited words"
+		}
+		// Смешанное написание приводим к кириллице только для русского списка
+		if hasCyrillic(token) && w.ru.match(cyrillicLookalikes.Replace(token)) ||
+			w.ru.match(token) {
+			return "text contains prohibited words"
+		}
+	}
+	return ""
+}
+
+// tokenize разбивает текст на слова в нижнем регистре.
+// Цифры остаются внутри слов, чтобы ловить подмены вроде "3" вместо "з"
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasCyrillic(token string) bool {
+	for _, r := range token {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+)
+
+// ErrInvalidWebhookURL - адрес не похож на webhook канала Discord
+var ErrInvalidWebhookURL = errors.New("expected a Discord webhook URL like https://discord.com/api/webhooks/<id>/<token>")
+
+// webhookPath - путь webhook в любом из вариантов URL (discord.com, discordapp.com, /api/v10/...)
+var webhookPath = regexp.MustCompile(`/api/(?:v\d+/)?webhooks/(\d+)/([\w-]+)/?$`)
+
+// ParseWebhookURL достает id и токен из URL вебхука. Хост не важен:
+// запросы всегда идут на DISCORD_API_URL
+func ParseWebhookURL(raw string) (string, string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", "", ErrInvalidWebhookURL
+	}
+	match := webhookPath.FindStringSubmatch(parsed.Path)
+	if match == nil {
+		return "", "", ErrInvalidWebhookURL
+	}
+	return match[1], match[2], nil
+}
+
+// APIError - неуспешный ответ Discord
+type APIError struct {
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("discord responded with status %d: %s", e.Status, e.Body)
+}
+
+// IsNotFound - вебхук или сообщение удалены в самом Discord
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+var (
+	clientOnce sync.Once
+	client     *http.Client
+)
+
+func httpClient() *http.Client {
+	clientOnce.Do(func() {
+		client = &http.Client{Timeout: config.DiscordConfig.Timeout}
+	})
+	return client
+}
+
+func webhookURL(webhookID, token string) string {
+	return fmt.Sprintf("%s/webhooks/%s/%s", config.DiscordConfig.APIURL, webhookID, token)
+}
+
+// request выполняет запрос к вебхуку и раскладывает JSON ответа в out (если out не nil)
+func request(method, endpoint string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", "Teamly-Discord/1.0")
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		// В тексте ошибки net/http есть URL с токеном вебхука - не отдаем его в логи
+		return fmt.Errorf("discord %s request failed", method)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &APIError{Status: resp.StatusCode, Body: string(raw)}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// CheckWebhook проверяет, что вебхук существует и токен подходит
+func CheckWebhook(webhookID, token string) error {
+	return request(http.MethodGet, webhookURL(webhookID, token), nil, nil)
+}
+
+func createMessage(channel *models.DiscordChannel, payload any) (string, error) {
+	var message struct {
+		ID string `json:"id"`
+	}
+	// wait=true - Discord возвращает созданное сообщение, его id нужен для правок
+	endpoint := webhookURL(channel.WebhookID, channel.WebhookToken) + "?wait=true"
+	if err := request(http.MethodPost, endpoint, payload, &message); err != nil {
+		return "", err
+	}
+	return message.ID, nil
+}
+
+func editMessage(channel *models.DiscordChannel, messageID string, payload any) error {
+	endpoint := webhookURL(channel.WebhookID, channel.WebhookToken) + "/messages/" + messageID
+	return request(http.MethodPatch, endpoint, payload, nil)
+}
+
+func deleteMessage(channel *models.DiscordChannel, messageID string) error {
+	endpoint := webhookURL(channel.WebhookID, channel.WebhookToken) + "/messages/" + messageID
+	if err := request(http.MethodDelete, endpoint, nil, nil); err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
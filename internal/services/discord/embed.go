@@ -0,0 +1,117 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/utils"
+)
+
+// Цвета полосы эмбеда: набор идет / набор закрыт
+const (
+	colorOpen   = 0x57F287
+	colorClosed = 0x99AAB5
+)
+
+// descriptionLimit - сколько символов описания заявки показывать в анонсе
+const descriptionLimit = 300
+
+// Подписей немного, поэтому они лежат здесь, а не в отдельных файлах переводов
+var labels = map[string]map[string]string{
+	"ru": {
+		"platform":   "Платформа",
+		"voice_chat": "Голосовой чат",
+		"time":       "Время",
+		"slots":      "Свободно мест",
+		"yes":        "Да",
+		"no":         "Нет",
+		"closed":     "Набор закрыт",
+		"footer":     "Teamly - поиск тиммейтов",
+	},
+	"en": {
+		"platform":   "Platform",
+		"voice_chat": "Voice chat",
+		"time":       "Time",
+		"slots":      "Slots left",
+		"yes":        "Yes",
+		"no":         "No",
+		"closed":     "Team is full",
+		"footer":     "Teamly - find teammates",
+	},
+}
+
+var platformNames = map[models.Platform]string{
+	models.PlatformPC:             "PC",
+	models.PlatformPlayStation:    "PlayStation",
+	models.PlatformXbox:           "Xbox",
+	models.PlatformNintendoSwitch: "Nintendo Switch",
+	models.PlatformMobile:         "Mobile",
+}
+
+func label(locale, key string) string {
+	return labels[utils.NormalizeLocale(locale)][key]
+}
+
+// messagePayload - тело сообщения с анонсом заявки. Упоминания отключены,
+// чтобы @everyone в названии заявки никого не пинговал
+func messagePayload(locale string, application *models.GameApplication) map[string]any {
+	slotsLeft := application.MaxPlayers - application.AcceptedPlayers
+	if slotsLeft < 0 {
+		slotsLeft = 0
+	}
+
+	title := application.Title
+	color := colorOpen
+	if application.IsFull {
+		title = "[" + label(locale, "closed") + "] " + title
+		color = colorClosed
+	}
+
+	voiceChat := label(locale, "no")
+	if application.WithVoiceChat {
+		voiceChat = label(locale, "yes")
+	}
+
+	platform, ok := platformNames[application.Platform]
+	if !ok {
+		platform = string(application.Platform)
+	}
+
+	embed := map[string]any{
+		"title":  truncate(title, 256),
+		"url":    fmt.Sprintf("%s/applications/%s", config.FrontendURL(), application.ID),
+		"color":  color,
+		"author": map[string]any{"name": application.User.Nickname},
+		"fields": []map[string]any{
+			{"name": label(locale, "platform"), "value": platform, "inline": true},
+			{"name": label(locale, "voice_chat"), "value": voiceChat, "inline": true},
+			{"name": label(locale, "slots"), "value": fmt.Sprintf("%d / %d", slotsLeft, application.MaxPlayers), "inline": true},
+			// Discord сам покажет время в часовом поясе читателя
+			{"name": label(locale, "time"), "value": fmt.Sprintf("<t:%d:f> - <t:%d:t>", application.PrimeTimeStart.Unix(), application.PrimeTimeEnd.Unix())},
+		},
+		"footer":    map[string]any{"text": label(locale, "footer")},
+		"timestamp": application.CreatedAt,
+	}
+	// Пустые строки Discord в эмбеде не принимает
+	if description := strings.TrimSpace(application.Description); description != "" {
+		embed["description"] = truncate(description, descriptionLimit)
+	}
+	if strings.HasPrefix(application.Game.Icon_url, "http") {
+		embed["thumbnail"] = map[string]any{"url": application.Game.Icon_url}
+	}
+
+	return map[string]any{
+		"embeds":           []map[string]any{embed},
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,200 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/outbox"
+	"github.com/duker221/teamly/internal/services/suspension"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StartWorker запускает фоновую синхронизацию анонсов: новые публичные заявки
+// публикуются, изменившиеся - правятся, истекшие и снятые - удаляются из канала
+func StartWorker() {
+	interval := config.DiscordConfig.PollInterval
+	if interval <= 0 {
+		log.Println("Discord announcements disabled")
+		return
+	}
+
+	posts := outbox.Queue[models.DiscordPost]{
+		Name:      "Discord announcement sync",
+		BatchSize: config.DiscordConfig.BatchSize,
+		Ready:     postsToSync,
+		Process:   syncBatch,
+	}
+	outbox.Every(interval, func() {
+		if err := queueNewApplications(); err != nil {
+			log.Printf("Failed to queue Discord announcements: %v", err)
+		}
+		posts.Drain()
+	})
+}
+
+// queueNewApplications заводит анонсы для открытых публичных заявок незаблокированных авторов,
+// созданных после подключения канала. Уникальный индекс не дает завести анонс дважды
+func queueNewApplications() error {
+	var pairs []struct {
+		ChannelID     uuid.UUID
+		ApplicationID uuid.UUID
+	}
+	if err := database.DB.Table("discord_channels AS c").
+		Select("c.id AS channel_id, a.id AS application_id").
+		Joins("JOIN game_applications a ON a.game_id = c.game_id AND a.created_at >= c.created_at").
+		Where("c.is_active = ?", true).
+		Where("a.is_active = ? AND a.is_hidden = ? AND a.is_full = ? AND a.visibility = ? AND a.prime_time_end > ?",
+			true, false, false, models.VisibilityPublic, time.Now()).
+		Where("a.user_id NOT IN (?)", suspension.Users()).
+		Where("NOT EXISTS (SELECT 1 FROM discord_posts p WHERE p.channel_id = c.id AND p.application_id = a.id)").
+		Scan(&pairs).Error; err != nil {
+		return err
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	posts := make([]models.DiscordPost, 0, len(pairs))
+	for _, pair := range pairs {
+		posts = append(posts, models.DiscordPost{
+			ChannelID:     pair.ChannelID,
+			ApplicationID: pair.ApplicationID,
+			State:         models.DiscordPostPending,
+		})
+	}
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&posts).Error
+}
+
+// postsToSync - анонсы активных каналов, которые еще не опубликованы, у которых заявка
+// изменилась после последней синхронизации, закончился прайм-тайм или автора заблокировали
+func postsToSync(tx *gorm.DB, now time.Time) *gorm.DB {
+	return tx.
+		Where("state IN ? AND next_attempt_at <= ?",
+			[]models.DiscordPostState{models.DiscordPostPending, models.DiscordPostOpen, models.DiscordPostClosed}, now).
+		Where("EXISTS (SELECT 1 FROM discord_channels c WHERE c.id = discord_posts.channel_id AND c.is_active)").
+		Where("state = ? OR NOT EXISTS (SELECT 1 FROM game_applications a WHERE a.id = discord_posts.application_id "+
+			"AND a.updated_at <= discord_posts.synced_at AND a.prime_time_end > ? AND a.user_id NOT IN (?))",
+			models.DiscordPostPending, now, suspension.Users())
+}
+
+// syncBatch синхронизирует пачку анонсов, загружая каждый канал один раз
+func syncBatch(tx *gorm.DB, batch []models.DiscordPost, now time.Time) error {
+	channels := make(map[uuid.UUID]*models.DiscordChannel)
+	for i := range batch {
+		post := &batch[i]
+		channel, ok := channels[post.ChannelID]
+		if !ok {
+			channel = &models.DiscordChannel{}
+			if err := tx.First(channel, post.ChannelID).Error; err != nil {
+				return err
+			}
+			channels[post.ChannelID] = channel
+		}
+		syncPost(tx, channel, post, now)
+	}
+	return nil
+}
+
+// syncPost приводит сообщение в канале к текущему состоянию заявки
+func syncPost(tx *gorm.DB, channel *models.DiscordChannel, post *models.DiscordPost, now time.Time) {
+	var application models.GameApplication
+	err := tx.
+		Preload("Game").
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname", "suspended_until", "banned_at")
+		}).
+		First(&application, post.ApplicationID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		recordFailure(post, err, now)
+		return
+	}
+
+	desired := models.DiscordPostRemoved
+	if err == nil {
+		desired = desiredState(&application, now)
+	}
+
+	state, err := publish(channel, post, &application, desired)
+	if err != nil {
+		recordFailure(post, err, now)
+		return
+	}
+
+	post.State = state
+	post.Attempts = 0
+	post.LastError = nil
+	if application.ID != uuid.Nil {
+		post.SyncedAt = &application.UpdatedAt
+	}
+}
+
+// desiredState - каким должен быть анонс для заявки в момент now. Анонсы
+// заблокированного автора удаляются так же, как скрытые модератором заявки
+func desiredState(application *models.GameApplication, now time.Time) models.DiscordPostState {
+	switch {
+	case !application.IsActive || application.IsHidden || application.User.IsSuspended(now) ||
+		application.Visibility != models.VisibilityPublic || !application.PrimeTimeEnd.After(now):
+		return models.DiscordPostRemoved
+	case application.IsFull:
+		return models.DiscordPostClosed
+	default:
+		return models.DiscordPostOpen
+	}
+}
+
+// publish создает, правит или удаляет сообщение и возвращает итоговое состояние анонса
+func publish(channel *models.DiscordChannel, post *models.DiscordPost, application *models.GameApplication, desired models.DiscordPostState) (models.DiscordPostState, error) {
+	switch {
+	case desired == models.DiscordPostRemoved:
+		if post.MessageID != "" {
+			if err := deleteMessage(channel, post.MessageID); err != nil {
+				return post.State, err
+			}
+		}
+		return models.DiscordPostRemoved, nil
+
+	case post.MessageID == "":
+		// Заявка заполнилась раньше, чем анонс успели опубликовать - публиковать нечего
+		if desired == models.DiscordPostClosed {
+			return models.DiscordPostRemoved, nil
+		}
+		messageID, err := createMessage(channel, messagePayload(channel.Locale, application))
+		if err != nil {
+			return post.State, err
+		}
+		post.MessageID = messageID
+		return desired, nil
+
+	default:
+		err := editMessage(channel, post.MessageID, messagePayload(channel.Locale, application))
+		if IsNotFound(err) {
+			// Сообщение удалили в самом Discord - больше его не трогаем
+			return models.DiscordPostRemoved, nil
+		}
+		if err != nil {
+			return post.State, err
+		}
+		return desired, nil
+	}
+}
+
+// recordFailure планирует повтор с экспоненциальной задержкой или сдается после MaxAttempts
+func recordFailure(post *models.DiscordPost, err error, now time.Time) {
+	post.Attempts++
+	message := err.Error()
+	post.LastError = &message
+
+	if post.Attempts >= config.DiscordConfig.MaxAttempts {
+		post.State = models.DiscordPostFailed
+		log.Printf("Discord announcement %s for application %s failed after %d attempts: %v", post.ID, post.ApplicationID, post.Attempts, err)
+		return
+	}
+	post.NextAttemptAt = now.Add(outbox.RetryDelay(post.Attempts, config.DiscordConfig.RetryBase, config.DiscordConfig.RetryMax))
+	log.Printf("Discord announcement %s for application %s failed (attempt %d), retry at %s: %v", post.ID, post.ApplicationID, post.Attempts, post.NextAttemptAt.Format(time.RFC3339), err)
+}
@@ -3,63 +3,143 @@ package email
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 
-	"github.com/resend/resend-go/v2"
+	"gorm.io/gorm"
 )
 
-var client *resend.Client
+var mailer Mailer
 var fromEmail string
 var frontendURL string
+var apiURL string
 
-// InitEmailService инициализирует email сервис с Resend API
+// InitEmailService выбирает транспорт по EMAIL_TRANSPORT: resend, smtp, file или memory.
+// Если транспорт не задан, используется Resend при наличии RESEND_API_KEY
 func InitEmailService() error {
-	apiKey := os.Getenv("RESEND_API_KEY")
-	if apiKey == "" {
-		log.Println("Warning: RESEND_API_KEY not set, email service disabled")
-		return nil
-	}
-
-	client = resend.NewClient(apiKey)
 	fromEmail = getEnvOrDefault("EMAIL_FROM", "Teamly <noreply@teamly.app>")
 	frontendURL = getEnvOrDefault("FRONTEND_URL", "http://localhost:3000")
+	apiURL = getEnvOrDefault("API_URL", "http://localhost:3001")
+
+	transport := os.Getenv("EMAIL_TRANSPORT")
+	if transport == "" && os.Getenv("RESEND_API_KEY") != "" {
+		transport = "resend"
+	}
+
+	switch transport {
+	case "":
+		log.Println("Warning: EMAIL_TRANSPORT and RESEND_API_KEY not set, email service disabled")
+		return nil
+	case "resend":
+		apiKey := os.Getenv("RESEND_API_KEY")
+		if apiKey == "" {
+			return fmt.Errorf("RESEND_API_KEY is required for resend transport")
+		}
+		mailer = NewResendMailer(apiKey)
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return fmt.Errorf("SMTP_HOST is required for smtp transport")
+		}
+		mailer = &SMTPMailer{
+			Host:     host,
+			Port:     getEnvOrDefault("SMTP_PORT", "1025"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			TLSMode:  getEnvOrDefault("SMTP_TLS", SMTPTLSStartTLS),
+		}
+	case "file":
+		mailer = &FileMailer{Dir: getEnvOrDefault("EMAIL_FILE_DIR", "tmp/emails")}
+	case "memory":
+		mailer = NewMemoryMailer()
+	default:
+		return fmt.Errorf("unknown EMAIL_TRANSPORT %q", transport)
+	}
 
-	log.Println("Email service initialized with Resend")
+	log.Printf("Email service initialized with %s transport", mailer.Name())
 	return nil
 }
 
+// SetMailer подменяет транспорт, например на MemoryMailer в интеграционных тестах
+func SetMailer(m Mailer) {
+	mailer = m
+}
+
+// CurrentMailer возвращает активный транспорт (nil, если отправка выключена)
+func CurrentMailer() Mailer {
+	return mailer
+}
+
 // IsEnabled проверяет, включен ли email сервис
 func IsEnabled() bool {
-	return client != nil
+	return mailer != nil
 }
 
-// SendPasswordResetEmail отправляет email с ссылкой для сброса пароля
-func SendPasswordResetEmail(toEmail, token string) error {
-	if !IsEnabled() {
-		log.Printf("Email service disabled, would send password reset to: %s", toEmail)
-		return nil
-	}
+// send отправляет письмо от имени EMAIL_FROM через выбранный транспорт
+func send(msg Message) (string, error) {
+	msg.From = fromEmail
+	return mailer.Send(msg)
+}
 
+// QueuePasswordResetEmail ставит в outbox письмо со ссылкой для сброса пароля на языке получателя
+func QueuePasswordResetEmail(db *gorm.DB, toEmail, locale, token string) error {
 	resetURL := fmt.Sprintf("%s/reset-password?token=%s", frontendURL, token)
-	htmlContent := GetPasswordResetEmailHTML(resetURL)
-	textContent := GetPasswordResetEmailText(resetURL)
+	subject := T(locale, "password_reset.subject", nil)
 
-	params := &resend.SendEmailRequest{
-		From:    fromEmail,
-		To:      []string{toEmail},
-		Subject: "Сброс пароля - Teamly",
-		Html:    htmlContent,
-		Text:    textContent,
+	html, text, err := Render(TemplatePasswordReset, locale, subject, map[string]any{
+		"ResetURL": resetURL,
+	})
+	if err != nil {
+		return err
 	}
 
-	sent, err := client.Emails.Send(params)
+	return Enqueue(db, KindPasswordReset, Message{
+		To:      []string{toEmail},
+		Subject: subject,
+		HTML:    html,
+		Text:    text,
+	})
+}
+
+// NotificationItem - строка письма-уведомления со ссылкой на объект
+type NotificationItem struct {
+	Text string
+	URL  string
+}
+
+// FrontendURL - адрес фронтенда для ссылок в письмах
+func FrontendURL() string {
+	return frontendURL
+}
+
+// QueueNotificationEmail ставит в outbox письмо с одним или несколькими уведомлениями.
+// unsubscribeToken используется для ссылки отписки и заголовков List-Unsubscribe
+func QueueNotificationEmail(db *gorm.DB, kind, toEmail, locale, subject, heading string, items []NotificationItem, unsubscribeToken string) error {
+	unsubscribeURL := fmt.Sprintf("%s/unsubscribe?token=%s", frontendURL, url.QueryEscape(unsubscribeToken))
+	oneClickURL := fmt.Sprintf("%s/api/email/unsubscribe?token=%s", apiURL, url.QueryEscape(unsubscribeToken))
+	settingsURL := fmt.Sprintf("%s/settings/notifications", frontendURL)
+
+	html, text, err := Render(TemplateNotification, locale, subject, map[string]any{
+		"Heading":        heading,
+		"Items":          items,
+		"SettingsURL":    settingsURL,
+		"UnsubscribeURL": unsubscribeURL,
+	})
 	if err != nil {
-		log.Printf("Failed to send password reset email to %s: %v", toEmail, err)
-		return fmt.Errorf("failed to send email: %w", err)
+		return err
 	}
 
-	log.Printf("Password reset email sent to %s, ID: %s", toEmail, sent.Id)
-	return nil
+	return Enqueue(db, kind, Message{
+		To:      []string{toEmail},
+		Subject: subject,
+		HTML:    html,
+		Text:    text,
+		// Отписка в один клик из почтового клиента (RFC 8058)
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + oneClickURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -0,0 +1,17 @@
+package email
+
+// Message - письмо, готовое к отправке любым транспортом
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
+
+// Mailer - транспорт отправки писем. Возвращает ID письма у провайдера (если есть)
+type Mailer interface {
+	Send(msg Message) (string, error)
+	Name() string
+}
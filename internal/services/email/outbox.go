@@ -0,0 +1,142 @@
+package email
+
+import (
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/outbox"
+	"gorm.io/gorm"
+)
+
+// KindPasswordReset - письмо со ссылкой сброса пароля
+const KindPasswordReset = "password_reset"
+
+// secretKinds - письма, в теле которых ссылка-секрет. Их тело стирается и после
+// неудачной доставки: повторять такое письмо нельзя, пользователь запросит новую ссылку
+var secretKinds = map[string]bool{
+	KindPasswordReset: true,
+}
+
+// Enqueue кладет письмо в outbox через db. Внутри транзакции письмо
+// уйдет только если бизнес-изменение закоммитится
+func Enqueue(db *gorm.DB, kind string, msg Message) error {
+	to := ""
+	if len(msg.To) > 0 {
+		to = msg.To[0]
+	}
+	return db.Create(&models.EmailOutbox{
+		Kind:    kind,
+		To:      to,
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+		Headers: msg.Headers,
+		Status:  models.EmailPending,
+	}).Error
+}
+
+// StartOutboxWorker запускает фоновую доставку писем из outbox
+func StartOutboxWorker() {
+	interval := config.EmailConfig.PollInterval
+	if interval <= 0 {
+		log.Println("Email outbox worker disabled")
+		return
+	}
+
+	emails := outbox.Queue[models.EmailOutbox]{
+		Name:      "Email outbox delivery",
+		BatchSize: config.EmailConfig.BatchSize,
+		Ready: func(tx *gorm.DB, now time.Time) *gorm.DB {
+			return tx.Where("status = ? AND next_attempt_at <= ?", models.EmailPending, now)
+		},
+		Process: func(_ *gorm.DB, batch []models.EmailOutbox, _ time.Time) error {
+			for i := range batch {
+				deliver(&batch[i])
+			}
+			return nil
+		},
+	}
+	outbox.Every(interval, emails.Drain)
+	outbox.Every(time.Hour, func() { purgeOutbox(time.Now()) })
+}
+
+// purgeOutbox стирает тела писем, которые больше не будут отправлены (на случай записей,
+// сохраненных до появления стирания), и удаляет записи старше EMAIL_OUTBOX_RETENTION
+func purgeOutbox(now time.Time) {
+	secret := make([]string, 0, len(secretKinds))
+	for kind := range secretKinds {
+		secret = append(secret, kind)
+	}
+	err := database.DB.Model(&models.EmailOutbox{}).
+		Where("redacted_at IS NULL").
+		Where("status = ? OR status <> ? AND kind IN ?", models.EmailSent, models.EmailPending, secret).
+		Updates(map[string]any{"html": "", "text": "", "headers": nil, "redacted_at": now}).Error
+	if err != nil {
+		log.Printf("Failed to redact delivered emails: %v", err)
+	}
+
+	if config.EmailConfig.Retention <= 0 {
+		return
+	}
+	result := database.DB.
+		Where("status IN ? AND updated_at < ?",
+			[]models.EmailStatus{models.EmailSent, models.EmailSkipped, models.EmailDead},
+			now.Add(-config.EmailConfig.Retention)).
+		Delete(&models.EmailOutbox{})
+	if result.Error != nil {
+		log.Printf("Failed to purge email outbox: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Purged %d old emails from outbox", result.RowsAffected)
+	}
+}
+
+// deliver делает одну попытку отправки и обновляет состояние записи
+func deliver(entry *models.EmailOutbox) {
+	now := time.Now()
+
+	if !IsEnabled() {
+		log.Printf("Email service disabled, would send %q to: %s", entry.Subject, entry.To)
+		entry.Status = models.EmailSkipped
+		if secretKinds[entry.Kind] {
+			entry.Redact(now)
+		}
+		return
+	}
+
+	entry.Attempts++
+	id, err := send(Message{
+		To:      []string{entry.To},
+		Subject: entry.Subject,
+		HTML:    entry.HTML,
+		Text:    entry.Text,
+		Headers: entry.Headers,
+	})
+	if err == nil {
+		entry.Status = models.EmailSent
+		entry.SentAt = &now
+		entry.ProviderID = &id
+		entry.LastError = nil
+		entry.Redact(now)
+		log.Printf("Email %s (%s) sent to %s, ID: %s", entry.ID, entry.Kind, entry.To, id)
+		return
+	}
+
+	message := err.Error()
+	entry.LastError = &message
+	if entry.Attempts >= config.EmailConfig.MaxAttempts {
+		entry.Status = models.EmailDead
+		if secretKinds[entry.Kind] {
+			entry.Redact(now)
+		}
+		log.Printf("Email %s (%s) to %s moved to dead letter after %d attempts: %v", entry.ID, entry.Kind, entry.To, entry.Attempts, err)
+		return
+	}
+
+	entry.NextAttemptAt = now.Add(outbox.RetryDelay(entry.Attempts, config.EmailConfig.RetryBase, config.EmailConfig.RetryMax))
+	log.Printf("Email %s (%s) to %s failed (attempt %d), retry at %s: %v", entry.ID, entry.Kind, entry.To, entry.Attempts, entry.NextAttemptAt.Format(time.RFC3339), err)
+}
@@ -0,0 +1,67 @@
+package email
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+)
+
+type failingMailer struct{}
+
+func (failingMailer) Name() string                 { return "failing" }
+func (failingMailer) Send(Message) (string, error) { return "", errors.New("smtp is down") }
+
+func withMailer(t *testing.T, m Mailer) {
+	t.Helper()
+	previous := mailer
+	SetMailer(m)
+	t.Cleanup(func() { mailer = previous })
+}
+
+func resetEmail() *models.EmailOutbox {
+	return &models.EmailOutbox{
+		Kind:    KindPasswordReset,
+		To:      "player@example.com",
+		Subject: "Reset",
+		HTML:    "<a href=\"/reset-password?token=secret\">reset</a>",
+		Text:    "/reset-password?token=secret",
+		Status:  models.EmailPending,
+	}
+}
+
+func TestDeliverRedactsSentEmail(t *testing.T) {
+	memory := NewMemoryMailer()
+	withMailer(t, memory)
+
+	entry := resetEmail()
+	deliver(entry)
+
+	if entry.Status != models.EmailSent {
+		t.Fatalf("status = %s, want sent", entry.Status)
+	}
+	if len(memory.Sent()) != 1 || memory.Sent()[0].Text != "/reset-password?token=secret" {
+		t.Fatal("email body was not delivered before redaction")
+	}
+	if entry.HTML != "" || entry.Text != "" || entry.RedactedAt == nil {
+		t.Fatal("sent email body is still stored")
+	}
+}
+
+func TestDeliverRedactsDeadSecretEmail(t *testing.T) {
+	withMailer(t, failingMailer{})
+	previous := config.EmailConfig
+	config.EmailConfig.MaxAttempts = 1
+	t.Cleanup(func() { config.EmailConfig = previous })
+
+	entry := resetEmail()
+	deliver(entry)
+
+	if entry.Status != models.EmailDead {
+		t.Fatalf("status = %s, want dead", entry.Status)
+	}
+	if entry.Text != "" || entry.RedactedAt == nil {
+		t.Fatal("dead password reset email still stores the reset link")
+	}
+}
@@ -0,0 +1,29 @@
+package email
+
+import "github.com/resend/resend-go/v2"
+
+// ResendMailer отправляет письма через Resend API
+type ResendMailer struct {
+	client *resend.Client
+}
+
+func NewResendMailer(apiKey string) *ResendMailer {
+	return &ResendMailer{client: resend.NewClient(apiKey)}
+}
+
+func (m *ResendMailer) Name() string { return "resend" }
+
+func (m *ResendMailer) Send(msg Message) (string, error) {
+	sent, err := m.client.Emails.Send(&resend.SendEmailRequest{
+		From:    msg.From,
+		To:      msg.To,
+		Subject: msg.Subject,
+		Html:    msg.HTML,
+		Text:    msg.Text,
+		Headers: msg.Headers,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sent.Id, nil
+}
@@ -0,0 +1,69 @@
+package email
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileMailer сохраняет письма в каталог как .eml - для локальной разработки
+type FileMailer struct {
+	Dir string
+}
+
+func (m *FileMailer) Name() string { return "file" }
+
+func (m *FileMailer) Send(msg Message) (string, error) {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return "", err
+	}
+
+	messageID := newMessageID("localhost")
+	data, err := buildMIME(msg, messageID)
+	if err != nil {
+		return "", err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "<", "", ">", "", " ", "_").Replace(strings.Join(msg.To, "_"))
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102-150405.000000"), recipient)
+	if err := os.WriteFile(filepath.Join(m.Dir, name), data, 0o644); err != nil {
+		return "", err
+	}
+	return messageID, nil
+}
+
+// MemoryMailer хранит отправленные письма в памяти - для тестов
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Name() string { return "memory" }
+
+func (m *MemoryMailer) Send(msg Message) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return fmt.Sprintf("memory-%d", len(m.sent)), nil
+}
+
+// Sent возвращает копию отправленных писем
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Reset очищает отправленные письма
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
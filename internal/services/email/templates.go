@@ -1,94 +1,136 @@
 package email
 
-import "fmt"
-
-// GetPasswordResetEmailHTML возвращает HTML шаблон письма для сброса пароля
-func GetPasswordResetEmailHTML(resetURL string) string {
-	return fmt.Sprintf(`<!DOCTYPE html>
-<html lang="ru">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Сброс пароля - Teamly</title>
-</head>
-<body style="margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; background-color: #0a0a0a;">
-    <table role="presentation" width="100%%" cellspacing="0" cellpadding="0" border="0" style="background-color: #0a0a0a;">
-        <tr>
-            <td align="center" style="padding: 40px 20px;">
-                <table role="presentation" width="600" cellspacing="0" cellpadding="0" border="0" style="background: linear-gradient(180deg, rgba(139, 92, 246, 0.1) 0%%, rgba(0, 0, 0, 0) 100%%); background-color: #18181b; border-radius: 16px; border: 1px solid rgba(255, 255, 255, 0.1);">
-                    <!-- Header -->
-                    <tr>
-                        <td align="center" style="padding: 40px 40px 20px;">
-                            <h1 style="margin: 0; font-size: 32px; font-weight: bold; color: #ffffff; letter-spacing: -0.5px;">Teamly</h1>
-                        </td>
-                    </tr>
-
-                    <!-- Content -->
-                    <tr>
-                        <td style="padding: 20px 40px;">
-                            <h2 style="margin: 0 0 16px; font-size: 24px; font-weight: 600; color: #ffffff;">Сброс пароля</h2>
-                            <p style="margin: 0 0 24px; font-size: 16px; line-height: 1.6; color: #a1a1aa;">
-                                Мы получили запрос на сброс пароля для вашего аккаунта. Нажмите на кнопку ниже, чтобы создать новый пароль.
-                            </p>
-
-                            <!-- Button -->
-                            <table role="presentation" width="100%%" cellspacing="0" cellpadding="0" border="0">
-                                <tr>
-                                    <td align="center" style="padding: 8px 0 24px;">
-                                        <a href="%s" style="display: inline-block; padding: 14px 32px; background: linear-gradient(135deg, #8b5cf6 0%%, #a78bfa 100%%); color: #ffffff; text-decoration: none; font-size: 16px; font-weight: 600; border-radius: 12px; box-shadow: 0 4px 14px rgba(139, 92, 246, 0.4);">
-                                            Сбросить пароль
-                                        </a>
-                                    </td>
-                                </tr>
-                            </table>
-
-                            <p style="margin: 0 0 16px; font-size: 14px; line-height: 1.6; color: #71717a;">
-                                Если кнопка не работает, скопируйте и вставьте эту ссылку в браузер:
-                            </p>
-                            <p style="margin: 0 0 24px; font-size: 14px; word-break: break-all; color: #8b5cf6;">
-                                %s
-                            </p>
-
-                            <div style="padding: 16px; background-color: rgba(251, 191, 36, 0.1); border-radius: 8px; border: 1px solid rgba(251, 191, 36, 0.2);">
-                                <p style="margin: 0; font-size: 14px; color: #fbbf24;">
-                                    <strong>Важно:</strong> Ссылка действительна в течение 1 часа. Если вы не запрашивали сброс пароля, проигнорируйте это письмо.
-                                </p>
-                            </div>
-                        </td>
-                    </tr>
-
-                    <!-- Footer -->
-                    <tr>
-                        <td style="padding: 30px 40px 40px;">
-                            <hr style="border: none; border-top: 1px solid rgba(255, 255, 255, 0.1); margin: 0 0 20px;">
-                            <p style="margin: 0; font-size: 13px; color: #52525b; text-align: center;">
-                                Это автоматическое сообщение. Пожалуйста, не отвечайте на него.
-                            </p>
-                            <p style="margin: 8px 0 0; font-size: 13px; color: #52525b; text-align: center;">
-                                &copy; 2025 Teamly. Все права защищены.
-                            </p>
-                        </td>
-                    </tr>
-                </table>
-            </td>
-        </tr>
-    </table>
-</body>
-</html>`, resetURL, resetURL)
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/duker221/teamly/internal/i18n"
+	"github.com/duker221/teamly/internal/utils"
+)
+
+// Шаблоны писем: общий layout и по файлу на письмо, которые определяют блок "content"
+// и при необходимости переопределяют "footer"
+//
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// Переводы: по JSON-файлу на язык, значения - text/template строки
+//
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// Названия шаблонов писем
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateNotification  = "notification"
+)
+
+// TemplateNames - все шаблоны, доступные для предпросмотра
+var TemplateNames = []string{TemplatePasswordReset, TemplateNotification}
+
+var (
+	locales       = i18n.MustLoad("email", localeFS, nil)
+	htmlTemplates = mustParseHTMLTemplates()
+	textTemplates = mustParseTextTemplates()
+)
+
+func templateFuncs() map[string]any {
+	return map[string]any{
+		"t": func(locale, key string) string { return T(locale, key, nil) },
+	}
 }
 
-// GetPasswordResetEmailText возвращает текстовую версию письма для сброса пароля
-func GetPasswordResetEmailText(resetURL string) string {
-	return fmt.Sprintf(`Сброс пароля - Teamly
+func mustParseHTMLTemplates() map[string]*htmltemplate.Template {
+	templates := make(map[string]*htmltemplate.Template, len(TemplateNames))
+	for _, name := range TemplateNames {
+		templates[name] = htmltemplate.Must(htmltemplate.New("layout.html").Funcs(templateFuncs()).
+			ParseFS(templateFS, "templates/layout.html", path.Join("templates", name+".html")))
+	}
+	return templates
+}
 
-Мы получили запрос на сброс пароля для вашего аккаунта.
+func mustParseTextTemplates() map[string]*texttemplate.Template {
+	templates := make(map[string]*texttemplate.Template, len(TemplateNames))
+	for _, name := range TemplateNames {
+		templates[name] = texttemplate.Must(texttemplate.New("layout.txt").Funcs(templateFuncs()).
+			ParseFS(templateFS, "templates/layout.txt", path.Join("templates", name+".txt")))
+	}
+	return templates
+}
+
+// T возвращает перевод ключа на язык locale, подставляя data в плейсхолдеры вида {{.Count}}.
+// Если перевода нет, берется язык по умолчанию, а затем сам ключ
+func T(locale, key string, data any) string {
+	return locales.T(locale, key, data)
+}
+
+// Render собирает HTML и текстовую версии письма name на языке locale.
+// В data дополнительно попадают Locale, Subject и Year для общего layout
+func Render(name, locale, subject string, data map[string]any) (string, string, error) {
+	htmlTemplate, ok := htmlTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", name)
+	}
+
+	payload := make(map[string]any, len(data)+3)
+	for key, value := range data {
+		payload[key] = value
+	}
+	payload["Locale"] = utils.NormalizeLocale(locale)
+	payload["Subject"] = subject
+	payload["Year"] = time.Now().Year()
+
+	var htmlBuf, textBuf bytes.Buffer
+	if err := htmlTemplate.Execute(&htmlBuf, payload); err != nil {
+		return "", "", fmt.Errorf("render %s.html: %w", name, err)
+	}
+	if err := textTemplates[name].Execute(&textBuf, payload); err != nil {
+		return "", "", fmt.Errorf("render %s.txt: %w", name, err)
+	}
+	return htmlBuf.String(), strings.TrimSpace(textBuf.String()) + "\n", nil
+}
 
-Чтобы создать новый пароль, перейдите по ссылке:
-%s
+// Preview рендерит шаблон на тестовых данных - для проверки верстки и переводов
+func Preview(name, locale string) (Message, error) {
+	var subject string
+	var data map[string]any
 
-Важно: Ссылка действительна в течение 1 часа. Если вы не запрашивали сброс пароля, проигнорируйте это письмо.
+	switch name {
+	case TemplatePasswordReset:
+		subject = T(locale, "password_reset.subject", nil)
+		data = map[string]any{
+			"ResetURL": frontendURL + "/reset-password?token=preview",
+		}
+	case TemplateNotification:
+		subject = T(locale, "notification.subject.many", map[string]any{"Count": 2})
+		data = map[string]any{
+			"Heading": T(locale, "notification.heading.many", nil),
+			"Items": []NotificationItem{
+				{
+					Text: T(locale, "notification.item.new_response", map[string]any{"Actor": "Nagibator", "Title": "CS2 Premier"}),
+					URL:  frontendURL + "/applications/preview",
+				},
+				{
+					Text: T(locale, "notification.item.new_messages", map[string]any{"Actor": "Nagibator", "Count": 3}),
+					URL:  frontendURL + "/messages/preview",
+				},
+			},
+			"SettingsURL":    frontendURL + "/settings/notifications",
+			"UnsubscribeURL": frontendURL + "/unsubscribe?token=preview",
+		}
+	default:
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
 
----
-Это автоматическое сообщение. Пожалуйста, не отвечайте на него.
-© 2025 Teamly. Все права защищены.`, resetURL)
+	html, text, err := Render(name, locale, subject, data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: subject, HTML: html, Text: text}, nil
 }
@@ -0,0 +1,232 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/email"
+	"github.com/duker221/teamly/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// emailBacklogLimit - старше этого уведомления на почту уже не отправляются
+const emailBacklogLimit = 7 * 24 * time.Hour
+
+// StartEmailWorker периодически ставит в outbox письма о новых уведомлениях
+// с учетом настроек пользователей: мгновенно, дайджестом или никак
+func StartEmailWorker() {
+	interval := config.NotificationConfig.EmailInterval
+	if interval <= 0 {
+		log.Println("Email notifications disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			sendPendingEmails()
+			<-ticker.C
+		}
+	}()
+}
+
+// EmailModes - режимы писем пользователя для всех типов событий с учетом значений по умолчанию
+func EmailModes(db *gorm.DB, userID uuid.UUID) map[models.NotificationType]models.EmailMode {
+	modes := make(map[models.NotificationType]models.EmailMode, len(models.EmailEventTypes))
+	for _, eventType := range models.EmailEventTypes {
+		modes[eventType] = models.DefaultEmailMode(eventType)
+	}
+
+	var preferences []models.EmailPreference
+	db.Where("user_id = ?", userID).Find(&preferences)
+	for _, preference := range preferences {
+		modes[preference.EventType] = preference.Mode
+	}
+	return modes
+}
+
+func sendPendingEmails() {
+	now := time.Now()
+	cfg := config.NotificationConfig
+
+	// Слишком старые уведомления просто помечаем обработанными
+	database.DB.Model(&models.Notification{}).
+		Where("emailed_at IS NULL AND created_at < ?", now.Add(-emailBacklogLimit)).
+		Update("emailed_at", now)
+
+	var pending []models.Notification
+	if err := database.DB.
+		Preload("Actor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname")
+		}).
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title")
+		}).
+		Where("emailed_at IS NULL AND type IN ?", models.EmailEventTypes).
+		// О сообщении пишем, только если его не прочитали за EmailMessageDelay
+		Where("(type <> ? OR created_at <= ?)", models.NotificationNewMessage, now.Add(-cfg.EmailMessageDelay)).
+		Order("created_at ASC").
+		Find(&pending).Error; err != nil {
+		log.Printf("Failed to load pending email notifications: %v", err)
+		return
+	}
+
+	byUser := make(map[uuid.UUID][]models.Notification)
+	for _, notification := range pending {
+		byUser[notification.UserID] = append(byUser[notification.UserID], notification)
+	}
+
+	for userID, notifications := range byUser {
+		if err := emailUser(userID, notifications, now); err != nil {
+			log.Printf("Failed to email notifications to user %s: %v", userID, err)
+		}
+	}
+}
+
+// emailUser раскладывает уведомления пользователя по режимам и отправляет
+// одно письмо со всеми мгновенными и, если подошло время, дайджест
+func emailUser(userID uuid.UUID, notifications []models.Notification, now time.Time) error {
+	var user models.User
+	if err := database.DB.Select("id", "email", "locale", "last_digest_at").First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	modes := EmailModes(database.DB, userID)
+
+	var instant, digest, skipped []models.Notification
+	for _, notification := range notifications {
+		switch {
+		case notification.Type == models.NotificationNewMessage && notification.ReadAt != nil:
+			// Диалог уже открыли - письмо не нужно
+			skipped = append(skipped, notification)
+		case modes[notification.Type] == models.EmailInstant:
+			instant = append(instant, notification)
+		case modes[notification.Type] == models.EmailDigest:
+			digest = append(digest, notification)
+		default:
+			skipped = append(skipped, notification)
+		}
+	}
+
+	if err := markEmailed(database.DB, skipped, now); err != nil {
+		return err
+	}
+
+	// Письмо и отметка об обработке уведомлений сохраняются вместе
+	if len(instant) > 0 {
+		subject, heading := instantSubject(user.Locale, instant)
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := email.QueueNotificationEmail(tx, "notification", user.Email, user.Locale, subject, heading, emailItems(user.Locale, instant), unsubscribeToken(userID, instant)); err != nil {
+				return err
+			}
+			return markEmailed(tx, instant, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	digestDue := user.LastDigestAt == nil || now.Sub(*user.LastDigestAt) >= config.NotificationConfig.DigestInterval
+	if len(digest) > 0 && digestDue {
+		subject := email.T(user.Locale, "notification.subject.digest", map[string]any{"Count": len(digest)})
+		heading := email.T(user.Locale, "notification.heading.digest", nil)
+		return database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := email.QueueNotificationEmail(tx, "digest", user.Email, user.Locale, subject, heading, emailItems(user.Locale, digest), unsubscribeToken(userID, digest)); err != nil {
+				return err
+			}
+			if err := markEmailed(tx, digest, now); err != nil {
+				return err
+			}
+			return tx.Model(&user).Update("last_digest_at", now).Error
+		})
+	}
+	return nil
+}
+
+func markEmailed(db *gorm.DB, notifications []models.Notification, now time.Time) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+	return db.Model(&models.Notification{}).Where("id IN ?", ids).Update("emailed_at", now).Error
+}
+
+// unsubscribeToken - отписка от типа события, если письмо только о нем, иначе от всех писем
+func unsubscribeToken(userID uuid.UUID, notifications []models.Notification) string {
+	event := string(notifications[0].Type)
+	for _, notification := range notifications[1:] {
+		if string(notification.Type) != event {
+			event = utils.UnsubscribeAll
+			break
+		}
+	}
+	return utils.GenerateUnsubscribeToken(userID, event)
+}
+
+// instantSubject - тема и заголовок письма: по типу события, если оно одно, иначе общие
+func instantSubject(locale string, notifications []models.Notification) (string, string) {
+	event := "many"
+	if len(notifications) == 1 {
+		event = string(notifications[0].Type)
+	}
+	data := map[string]any{"Count": len(notifications)}
+	return email.T(locale, "notification.subject."+event, data), email.T(locale, "notification.heading."+event, data)
+}
+
+func emailItems(locale string, notifications []models.Notification) []email.NotificationItem {
+	items := make([]email.NotificationItem, 0, len(notifications))
+	for _, notification := range notifications {
+		items = append(items, emailItem(locale, notification))
+	}
+	return items
+}
+
+func emailItem(locale string, n models.Notification) email.NotificationItem {
+	actor := email.T(locale, "notification.item.someone", nil)
+	if n.Actor != nil {
+		actor = n.Actor.Nickname
+	}
+	title := ""
+	applicationURL := email.FrontendURL()
+	if n.Application != nil {
+		title = n.Application.Title
+		applicationURL = fmt.Sprintf("%s/applications/%s", email.FrontendURL(), n.Application.ID)
+	}
+	data := map[string]any{"Actor": actor, "Title": title}
+
+	switch n.Type {
+	case models.NotificationNewResponse:
+		return email.NotificationItem{
+			Text: email.T(locale, "notification.item.new_response", data),
+			URL:  applicationURL,
+		}
+	case models.NotificationResponseAccepted:
+		return email.NotificationItem{
+			Text: email.T(locale, "notification.item.response_accepted", data),
+			URL:  applicationURL,
+		}
+	default:
+		text := email.T(locale, "notification.item.new_message", data)
+		if count, ok := n.Data["count"].(float64); ok && count > 1 {
+			data["Count"] = int(count)
+			text = email.T(locale, "notification.item.new_messages", data)
+		}
+		if preview, ok := n.Data["preview"].(string); ok && preview != "" {
+			text += ": " + preview
+		}
+		url := email.FrontendURL() + "/messages"
+		if n.EntityID != nil {
+			url = fmt.Sprintf("%s/messages/%s", email.FrontendURL(), n.EntityID)
+		}
+		return email.NotificationItem{Text: text, URL: url}
+	}
+}
@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// messagePreviewLength - сколько символов сообщения показывать в уведомлении
+const messagePreviewLength = 100
+
+// Notify сохраняет уведомление через db - внутри транзакции оно откатится вместе с изменением.
+// О собственных действиях пользователя не уведомляем
+func Notify(db *gorm.DB, notification models.Notification) error {
+	if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+		return nil
+	}
+	return db.Create(&notification).Error
+}
+
+// NotifyNewMessage уведомляет о новом сообщении. Пока предыдущее уведомление
+// по этому диалогу не прочитано, оно обновляется вместо создания нового
+func NotifyNewMessage(db *gorm.DB, recipientID, senderID, conversationID uuid.UUID, content string) error {
+	preview := []rune(content)
+	if len(preview) > messagePreviewLength {
+		preview = append(preview[:messagePreviewLength], '…')
+	}
+
+	var existing models.Notification
+	err := db.Where("user_id = ? AND type = ? AND entity_id = ? AND read_at IS NULL",
+		recipientID, models.NotificationNewMessage, conversationID).
+		First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return Notify(db, models.Notification{
+			UserID:   recipientID,
+			Type:     models.NotificationNewMessage,
+			ActorID:  &senderID,
+			EntityID: &conversationID,
+			Data:     map[string]interface{}{"preview": string(preview), "count": 1},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	count := 1
+	if value, ok := existing.Data["count"].(float64); ok {
+		count = int(value)
+	}
+	existing.Data = map[string]interface{}{"preview": string(preview), "count": count + 1}
+	existing.ActorID = &senderID
+	// Новое сообщение еще раз уходит в Telegram
+	existing.TelegramSentAt = nil
+	// Поднимаем уведомление наверх списка
+	existing.CreatedAt = time.Now()
+	return db.Save(&existing).Error
+}
+
+// StartExpiryWatcher периодически предупреждает авторов о заявках, время которых подходит к концу
+func StartExpiryWatcher() {
+	interval := config.NotificationConfig.ExpiryCheckInterval
+	if interval <= 0 {
+		log.Println("Application expiry notifications disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			notifyExpiringApplications()
+			<-ticker.C
+		}
+	}()
+}
+
+func notifyExpiringApplications() {
+	now := time.Now()
+
+	var applications []models.GameApplication
+	if err := database.DB.
+		Select("id", "user_id", "title", "prime_time_end").
+		Where("is_active = ? AND prime_time_end BETWEEN ? AND ?", true, now, now.Add(config.NotificationConfig.ExpiryLead)).
+		Where("NOT EXISTS (SELECT 1 FROM notifications WHERE notifications.application_id = game_applications.id AND notifications.type = ?)",
+			models.NotificationApplicationExpiring).
+		Find(&applications).Error; err != nil {
+		log.Printf("Failed to find expiring applications: %v", err)
+		return
+	}
+
+	for _, application := range applications {
+		err := Notify(database.DB, models.Notification{
+			UserID:        application.UserId,
+			Type:          models.NotificationApplicationExpiring,
+			ApplicationID: &application.ID,
+			Data: map[string]interface{}{
+				"title":      application.Title,
+				"expires_at": application.PrimeTimeEnd,
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to notify about expiring application %s: %v", application.ID, err)
+		}
+	}
+}
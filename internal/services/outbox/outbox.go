@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"log"
+	"time"
+
+	"github.com/duker221/teamly/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Queue - таблица-очередь с колонкой next_attempt_at, которую разбирает фоновый воркер:
+// письма, доставки вебхуков, анонсы Discord. Сервис задает только отбор готовых записей
+// и их обработку, выборка пачек и сохранение результата общие
+type Queue[T any] struct {
+	// Название для логов
+	Name string
+	// Сколько записей обрабатывается в одной транзакции
+	BatchSize int
+	// Ready отбирает записи, которые пора обработать в момент now
+	Ready func(tx *gorm.DB, now time.Time) *gorm.DB
+	// Process обрабатывает пачку внутри ее транзакции, после чего записи сохраняются
+	Process func(tx *gorm.DB, batch []T, now time.Time) error
+}
+
+// Drain обрабатывает пачки, пока в очереди есть готовые записи
+func (q Queue[T]) Drain() {
+	for q.processBatch() == q.BatchSize {
+	}
+}
+
+// processBatch обрабатывает одну пачку и возвращает ее размер.
+// SKIP LOCKED позволяет запускать несколько экземпляров API без двойной обработки
+func (q Queue[T]) processBatch() int {
+	now := time.Now()
+	processed := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var batch []T
+		if err := q.Ready(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}), now).
+			Order("next_attempt_at ASC").
+			Limit(q.BatchSize).
+			Find(&batch).Error; err != nil {
+			return err
+		}
+
+		if err := q.Process(tx, batch, now); err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := tx.Save(&batch[i]).Error; err != nil {
+				return err
+			}
+		}
+		processed = len(batch)
+		return nil
+	})
+	if err != nil {
+		log.Printf("%s failed: %v", q.Name, err)
+		return 0
+	}
+	return processed
+}
+
+// Every вызывает tick сразу и затем раз в interval в фоновой горутине
+func Every(interval time.Duration, tick func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			tick()
+			<-ticker.C
+		}
+	}()
+}
+
+// RetryDelay - экспоненциальная задержка перед следующей попыткой:
+// base после первой неудачи, дальше вдвое больше, но не больше max
+func RetryDelay(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,22 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelayDoublesUpToMax(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 8 * time.Minute,
+		5: 10 * time.Minute,
+		9: 10 * time.Minute,
+	}
+	for attempts, want := range cases {
+		if got := RetryDelay(attempts, time.Minute, 10*time.Minute); got != want {
+			t.Errorf("RetryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/duker221/teamly/internal/config"
+)
+
+// ErrStatsDisabled - не задан STEAM_API_KEY, библиотеку загрузить нельзя
+var ErrStatsDisabled = errors.New("steam web api key is not configured")
+
+// ErrProfileNotFound - Steam не знает такой SteamID
+var ErrProfileNotFound = errors.New("steam profile not found")
+
+// communityVisibilityPublic - значение communityvisibilitystate у открытого профиля
+const communityVisibilityPublic = 3
+
+// PlayerSummary - публичные данные профиля из ISteamUser/GetPlayerSummaries
+type PlayerSummary struct {
+	SteamID                  string `json:"steamid"`
+	PersonaName              string `json:"personaname"`
+	ProfileURL               string `json:"profileurl"`
+	AvatarFull               string `json:"avatarfull"`
+	CommunityVisibilityState int    `json:"communityvisibilitystate"`
+}
+
+// OwnedGame - игра из IPlayerService/GetOwnedGames; время в минутах
+type OwnedGame struct {
+	AppID           int    `json:"appid"`
+	Name            string `json:"name"`
+	PlaytimeForever int    `json:"playtime_forever"`
+}
+
+// APIError - неуспешный ответ Steam Web API
+type APIError struct {
+	Method string
+	Status int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("steam %s responded with status %d", e.Method, e.Status)
+}
+
+var (
+	clientOnce sync.Once
+	client     *http.Client
+)
+
+func httpClient() *http.Client {
+	clientOnce.Do(func() {
+		client = &http.Client{Timeout: config.SteamConfig.Timeout}
+	})
+	return client
+}
+
+// call вызывает метод Web API (interface/method/version) и раскладывает JSON ответа в out
+func call(method string, params url.Values, out any) error {
+	cfg := config.SteamConfig
+	if !cfg.StatsEnabled() {
+		return ErrStatsDisabled
+	}
+
+	params.Set("key", cfg.APIKey)
+	params.Set("format", "json")
+	resp, err := httpClient().Get(cfg.APIURL + "/" + method + "/?" + params.Encode())
+	if err != nil {
+		// В тексте ошибки net/http есть URL с ключом API - не отдаем его в логи
+		return fmt.Errorf("steam %s: request failed", method)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Method: method, Status: resp.StatusCode}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("steam %s: invalid response", method)
+	}
+	return nil
+}
+
+// GetPlayerSummary - публичные данные профиля
+func GetPlayerSummary(steamID string) (*PlayerSummary, error) {
+	var result struct {
+		Response struct {
+			Players []PlayerSummary `json:"players"`
+		} `json:"response"`
+	}
+	if err := call("ISteamUser/GetPlayerSummaries/v2", url.Values{"steamids": {steamID}}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Response.Players) == 0 {
+		return nil, ErrProfileNotFound
+	}
+	return &result.Response.Players[0], nil
+}
+
+// GetOwnedGames - библиотека игр и наигранное время. У закрытых профилей Steam
+// возвращает пустой ответ без списка - тогда visible равно false
+func GetOwnedGames(steamID string) (games []OwnedGame, visible bool, err error) {
+	var result struct {
+		Response struct {
+			GameCount *int        `json:"game_count"`
+			Games     []OwnedGame `json:"games"`
+		} `json:"response"`
+	}
+	params := url.Values{
+		"steamid":                   {steamID},
+		"include_appinfo":           {"1"},
+		"include_played_free_games": {"1"},
+	}
+	if err := call("IPlayerService/GetOwnedGames/v1", params, &result); err != nil {
+		return nil, false, err
+	}
+	return result.Response.Games, result.Response.GameCount != nil, nil
+}
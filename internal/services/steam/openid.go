@@ -0,0 +1,189 @@
+package steam
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidState - ссылка на вход подделана, выдана другому пользователю или устарела
+var ErrInvalidState = errors.New("invalid or expired steam login state")
+
+// ErrAssertionRejected - Steam не подтвердил ответ OpenID
+var ErrAssertionRejected = errors.New("steam did not confirm the openid assertion")
+
+const (
+	openIDNamespace  = "http://specs.openid.net/auth/2.0"
+	identifierSelect = "http://specs.openid.net/auth/2.0/identifier_select"
+)
+
+// signedFields - поля ответа, которые обязательно должны входить в подпись Steam (openid.signed).
+// Иначе check_authentication подтвердит подпись, а подставленные поля останутся непроверенными
+var signedFields = []string{"op_endpoint", "claimed_id", "identity", "return_to", "response_nonce"}
+
+// claimedIDPattern - идентификатор, который Steam выдает после входа; в конце 64-битный SteamID
+var claimedIDPattern = regexp.MustCompile(`^https?://steamcommunity\.com/openid/id/(\d{17})$`)
+
+// NewNonce - случайное значение для cookie браузера, начавшего привязку
+func NewNonce() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// LoginURL - адрес входа через Steam (OpenID 2.0). state связывает возврат
+// из Steam с пользователем, начавшим привязку, а nonce - с его браузером:
+// чужую ссылку на вход нельзя подсунуть другому пользователю
+func LoginURL(userID uuid.UUID, nonce string) string {
+	returnTo := returnURL(newState(userID, time.Now().Add(config.SteamConfig.LinkTTL), nonce))
+	realm, err := url.Parse(returnTo)
+	params := url.Values{
+		"openid.ns":         {openIDNamespace},
+		"openid.mode":       {"checkid_setup"},
+		"openid.return_to":  {returnTo},
+		"openid.identity":   {identifierSelect},
+		"openid.claimed_id": {identifierSelect},
+	}
+	if err == nil {
+		params.Set("openid.realm", realm.Scheme+"://"+realm.Host)
+	}
+	return config.SteamConfig.OpenIDURL + "?" + params.Encode()
+}
+
+// VerifyLogin проверяет параметры, с которыми Steam вернул пользователя на callback,
+// и возвращает пользователя из state и подтвержденный SteamID. nonce - значение
+// из cookie браузера, которому была выдана ссылка на вход
+func VerifyLogin(query url.Values, nonce string) (uuid.UUID, string, error) {
+	state := query.Get("state")
+	userID, err := parseState(state, nonce)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	if query.Get("openid.mode") != "id_res" || query.Get("openid.return_to") != returnURL(state) {
+		return uuid.Nil, "", ErrAssertionRejected
+	}
+	// Ответ должен прийти от настроенного провайдера, иначе check_authentication
+	// подтвердил бы ответ, подписанный кем-то другим
+	if query.Get("openid.op_endpoint") != config.SteamConfig.OpenIDURL || !coversSignedFields(query.Get("openid.signed")) {
+		return uuid.Nil, "", ErrAssertionRejected
+	}
+	claimedID := query.Get("openid.claimed_id")
+	match := claimedIDPattern.FindStringSubmatch(claimedID)
+	if match == nil || query.Get("openid.identity") != claimedID {
+		return uuid.Nil, "", ErrAssertionRejected
+	}
+
+	// Подпись ответа проверяет сам Steam: отправляем ему те же параметры с mode=check_authentication
+	params := url.Values{}
+	for key, values := range query {
+		if strings.HasPrefix(key, "openid.") {
+			params[key] = values
+		}
+	}
+	params.Set("openid.mode", "check_authentication")
+
+	resp, err := httpClient().PostForm(config.SteamConfig.OpenIDURL, params)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK || !isValidAssertion(string(body)) {
+		return uuid.Nil, "", ErrAssertionRejected
+	}
+
+	return userID, match[1], nil
+}
+
+// coversSignedFields - входят ли все signedFields в список подписанных полей
+func coversSignedFields(signed string) bool {
+	covered := make(map[string]bool)
+	for _, field := range strings.Split(signed, ",") {
+		covered[field] = true
+	}
+	for _, field := range signedFields {
+		if !covered[field] {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidAssertion ищет is_valid:true в ответе формата "ключ:значение" построчно
+func isValidAssertion(body string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "is_valid:true" {
+			return true
+		}
+	}
+	return false
+}
+
+func returnURL(state string) string {
+	return config.SteamConfig.CallbackURL + "?state=" + url.QueryEscape(state)
+}
+
+// newState - base64url(userID:expires) + "." + base64url(hmac). nonce входит только
+// в подпись: в адресе он не виден, и подделать state без cookie нельзя
+func newState(userID uuid.UUID, expires time.Time, nonce string) string {
+	payload := userID.String() + ":" + strconv.FormatInt(expires.Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(stateSignature(payload, nonce))
+}
+
+func parseState(state, nonce string) (uuid.UUID, error) {
+	if nonce == "" {
+		return uuid.Nil, ErrInvalidState
+	}
+	payloadPart, signaturePart, found := strings.Cut(state, ".")
+	if !found {
+		return uuid.Nil, ErrInvalidState
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return uuid.Nil, ErrInvalidState
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil || !hmac.Equal(signature, stateSignature(string(payload), nonce)) {
+		return uuid.Nil, ErrInvalidState
+	}
+
+	userPart, expiresPart, found := strings.Cut(string(payload), ":")
+	if !found {
+		return uuid.Nil, ErrInvalidState
+	}
+	expires, err := strconv.ParseInt(expiresPart, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return uuid.Nil, ErrInvalidState
+	}
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		return uuid.Nil, ErrInvalidState
+	}
+	return userID, nil
+}
+
+func stateSignature(payload, nonce string) []byte {
+	mac := hmac.New(sha256.New, config.SigningConfig.SteamState)
+	mac.Write([]byte("steam-login:" + payload + ":" + nonce))
+	return mac.Sum(nil)[:16]
+}
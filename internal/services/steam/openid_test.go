@@ -0,0 +1,108 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+const testSteamID = "76561197960287930"
+
+// withOpenIDServer подставляет провайдер OpenID, который подтверждает любой ответ:
+// отклонить подделку должны собственные проверки VerifyLogin
+func withOpenIDServer(t *testing.T) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:true\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	previousSteam, previousSigning := config.SteamConfig, config.SigningConfig
+	config.SteamConfig = config.SteamAPIConfig{
+		OpenIDURL:   server.URL,
+		CallbackURL: "https://api.example.com/api/steam/callback",
+		LinkTTL:     time.Minute,
+		Timeout:     time.Second,
+	}
+	config.SigningConfig.SteamState = []byte("test-steam-state-key")
+	t.Cleanup(func() {
+		config.SteamConfig = previousSteam
+		config.SigningConfig = previousSigning
+	})
+}
+
+// assertion - ответ Steam, с которым пользователь возвращается на callback
+func assertion(userID uuid.UUID, nonce string) url.Values {
+	state := newState(userID, time.Now().Add(time.Minute), nonce)
+	claimedID := "https://steamcommunity.com/openid/id/" + testSteamID
+	return url.Values{
+		"state":                 {state},
+		"openid.ns":             {openIDNamespace},
+		"openid.mode":           {"id_res"},
+		"openid.op_endpoint":    {config.SteamConfig.OpenIDURL},
+		"openid.claimed_id":     {claimedID},
+		"openid.identity":       {claimedID},
+		"openid.return_to":      {returnURL(state)},
+		"openid.response_nonce": {"2026-10-18T00:00:00Zabc"},
+		"openid.assoc_handle":   {"1234567890"},
+		"openid.signed":         {"signed,op_endpoint,claimed_id,identity,return_to,response_nonce,assoc_handle"},
+		"openid.sig":            {"signature"},
+	}
+}
+
+func TestVerifyLoginAcceptsValidAssertion(t *testing.T) {
+	withOpenIDServer(t)
+	userID := uuid.New()
+
+	gotUser, gotSteamID, err := VerifyLogin(assertion(userID, "nonce"), "nonce")
+	if err != nil || gotUser != userID || gotSteamID != testSteamID {
+		t.Fatalf("VerifyLogin = %v, %q, %v; want %v, %q", gotUser, gotSteamID, err, userID, testSteamID)
+	}
+}
+
+// Ссылку на вход, выданную одному браузеру, нельзя завершить в другом
+func TestVerifyLoginRequiresBrowserNonce(t *testing.T) {
+	withOpenIDServer(t)
+	query := assertion(uuid.New(), "nonce")
+
+	for _, nonce := range []string{"", "other-nonce"} {
+		if _, _, err := VerifyLogin(query, nonce); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("nonce %q: err = %v, want ErrInvalidState", nonce, err)
+		}
+	}
+}
+
+func TestVerifyLoginRejectsUnsignedOrForeignAssertion(t *testing.T) {
+	withOpenIDServer(t)
+
+	cases := map[string]func(url.Values){
+		"foreign op_endpoint": func(q url.Values) { q.Set("openid.op_endpoint", "https://evil.example.com/openid/login") },
+		"identity mismatch":   func(q url.Values) { q.Set("openid.identity", "https://steamcommunity.com/openid/id/76561197960287931") },
+	}
+	for _, field := range signedFields {
+		cases["unsigned "+field] = func(q url.Values) {
+			var signed []string
+			for _, name := range strings.Split(q.Get("openid.signed"), ",") {
+				if name != field {
+					signed = append(signed, name)
+				}
+			}
+			q.Set("openid.signed", strings.Join(signed, ","))
+		}
+	}
+
+	for name, tamper := range cases {
+		query := assertion(uuid.New(), "nonce")
+		tamper(query)
+		if _, _, err := VerifyLogin(query, "nonce"); !errors.Is(err, ErrAssertionRejected) {
+			t.Errorf("%s: err = %v, want ErrAssertionRejected", name, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package steam
+
+import (
+	"errors"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAccountTaken - этот аккаунт Steam уже привязан к другому пользователю
+var ErrAccountTaken = errors.New("steam account is linked to another user")
+
+// ErrNotLinked - у пользователя нет привязанного Steam
+var ErrNotLinked = errors.New("steam account is not linked")
+
+// ErrRefreshTooSoon - данные обновлялись меньше STEAM_REFRESH_COOLDOWN назад
+var ErrRefreshTooSoon = errors.New("steam profile was refreshed recently")
+
+// Link привязывает SteamID к пользователю. Повторная привязка того же аккаунта не ошибка
+func Link(userID uuid.UUID, steamID string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var owner models.User
+		err := tx.Select("id").Where("steam_id = ?", steamID).First(&owner).Error
+		if err == nil && owner.ID != userID {
+			return ErrAccountTaken
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Другой аккаунт Steam - данные прежнего больше не подтверждают часы
+		var user models.User
+		if err := tx.Select("id", "steam_id").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.SteamID != nil && *user.SteamID != steamID {
+			if err := forget(tx, userID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]any{"steam_id": steamID, "steam_linked_at": now}).Error
+	})
+}
+
+// Unlink отвязывает Steam и убирает подтвержденные им часы
+func Unlink(userID uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ? AND steam_id IS NOT NULL", userID).
+			Updates(map[string]any{"steam_id": nil, "steam_linked_at": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotLinked
+		}
+		return forget(tx, userID)
+	})
+}
+
+// forget удаляет кеш профиля и подтвержденные часы пользователя
+func forget(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.SteamProfile{}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.UserGameProfile{}).Where("user_id = ?", userID).
+		Updates(map[string]any{"verified_hours": nil, "verified_at": nil}).Error
+}
+
+// Profile - данные Steam пользователя. Из кеша, если они свежее STEAM_CACHE_TTL,
+// иначе загружаются заново; при недоступности Steam отдается устаревший кеш
+func Profile(userID uuid.UUID) (*models.SteamProfile, error) {
+	var cached models.SteamProfile
+	err := database.DB.First(&cached, "user_id = ?", userID).Error
+	if err == nil && time.Since(cached.FetchedAt) < config.SteamConfig.CacheTTL {
+		return &cached, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	fresh, refreshErr := fetch(userID)
+	if refreshErr != nil && err == nil {
+		return &cached, nil
+	}
+	return fresh, refreshErr
+}
+
+// Refresh загружает данные заново по запросу пользователя, но не чаще STEAM_REFRESH_COOLDOWN
+func Refresh(userID uuid.UUID) (*models.SteamProfile, error) {
+	var cached models.SteamProfile
+	if err := database.DB.Select("fetched_at").First(&cached, "user_id = ?", userID).Error; err == nil &&
+		time.Since(cached.FetchedAt) < config.SteamConfig.RefreshCooldown {
+		return nil, ErrRefreshTooSoon
+	}
+	return fetch(userID)
+}
+
+// fetch загружает профиль и библиотеку из Steam Web API, сохраняет их в кеш
+// и пересчитывает подтвержденные часы в игровых профилях
+func fetch(userID uuid.UUID) (*models.SteamProfile, error) {
+	var user models.User
+	if err := database.DB.Select("id", "steam_id").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.SteamID == nil {
+		return nil, ErrNotLinked
+	}
+
+	summary, err := GetPlayerSummary(*user.SteamID)
+	if err != nil {
+		return nil, err
+	}
+	owned, visible, err := GetOwnedGames(*user.SteamID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := models.SteamProfile{
+		UserID:       userID,
+		SteamID:      *user.SteamID,
+		PersonaName:  summary.PersonaName,
+		ProfileURL:   summary.ProfileURL,
+		AvatarURL:    summary.AvatarFull,
+		GamesVisible: visible && summary.CommunityVisibilityState == communityVisibilityPublic,
+		Games:        make([]models.SteamOwnedGame, 0, len(owned)),
+		FetchedAt:    time.Now(),
+	}
+	for _, game := range owned {
+		profile.Games = append(profile.Games, models.SteamOwnedGame{
+			AppID:           game.AppID,
+			Name:            game.Name,
+			PlaytimeMinutes: game.PlaytimeForever,
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&profile).Error; err != nil {
+			return err
+		}
+		var gameProfiles []models.UserGameProfile
+		if err := tx.Where("user_id = ?", userID).Find(&gameProfiles).Error; err != nil {
+			return err
+		}
+		for i := range gameProfiles {
+			if err := ApplyVerifiedHours(tx, &gameProfiles[i], &profile); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ApplyVerifiedHours проставляет игровому профилю часы из библиотеки Steam.
+// Если игры нет в Steam или библиотека закрыта, подтверждение снимается
+func ApplyVerifiedHours(tx *gorm.DB, gameProfile *models.UserGameProfile, steamProfile *models.SteamProfile) error {
+	var game models.Game
+	if err := tx.Select("id", "steam_app_id").First(&game, gameProfile.GameID).Error; err != nil {
+		return err
+	}
+
+	var hours *int
+	var verifiedAt *time.Time
+	if game.SteamAppID != nil && steamProfile != nil && steamProfile.GamesVisible {
+		if minutes, ok := steamProfile.Playtime(*game.SteamAppID); ok {
+			value := minutes / 60
+			hours = &value
+			verifiedAt = &steamProfile.FetchedAt
+		}
+	}
+
+	gameProfile.VerifiedHours = hours
+	gameProfile.VerifiedAt = verifiedAt
+	return tx.Model(gameProfile).Select("verified_hours", "verified_at").Updates(gameProfile).Error
+}
+
+// CachedProfile - данные Steam из кеша без обращения к Steam; nil, если их нет
+func CachedProfile(userID uuid.UUID) *models.SteamProfile {
+	var cached models.SteamProfile
+	if err := database.DB.First(&cached, "user_id = ?", userID).Error; err != nil {
+		return nil
+	}
+	return &cached
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"gorm.io/gorm"
+)
+
+// cleanupBatchSize - сколько ключей проверять на ссылки одним запросом
+const cleanupBatchSize = 500
+
+// referenceQueries - откуда берутся ссылки на файлы: префикс ключей -> запрос,
+// который из переданных ключей возвращает те, что еще используются.
+// Файлы с префиксами не из этого списка очистка не трогает
+var referenceQueries = map[string]string{
+	"avatars/": `SELECT DISTINCT key FROM users CROSS JOIN LATERAL jsonb_array_elements_text(users.avatar_keys) AS key WHERE key IN ?`,
+}
+
+// StartCleanup запускает периодическое удаление файлов, на которые не осталось ссылок.
+// Файлы с ключами по содержимому могут быть общими, поэтому обработчики их не удаляют
+func StartCleanup() {
+	interval := config.StorageConfig.CleanupInterval
+	if interval <= 0 {
+		log.Println("Storage cleanup disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for prefix, query := range referenceQueries {
+				deleted, err := cleanupPrefix(prefix, query)
+				if err != nil {
+					log.Printf("Storage cleanup of %s failed: %v", prefix, err)
+				}
+				if deleted > 0 {
+					log.Printf("Storage cleanup: deleted %d orphaned files from %s", deleted, prefix)
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// cleanupPrefix удаляет файлы префикса без ссылок. Свежие файлы не трогаем:
+// ссылка на только что загруженный файл появляется в базе уже после загрузки
+func cleanupPrefix(prefix, query string) (int, error) {
+	threshold := time.Now().Add(-config.StorageConfig.OrphanTTL)
+
+	var candidates []string
+	err := Default.List(prefix, func(object Object) error {
+		if object.ModTime.Before(threshold) {
+			candidates = append(candidates, object.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for start := 0; start < len(candidates); start += cleanupBatchSize {
+		batch := candidates[start:min(start+cleanupBatchSize, len(candidates))]
+
+		var referenced []string
+		if err := database.DB.Raw(query, batch).Scan(&referenced).Error; err != nil {
+			return deleted, err
+		}
+		used := make(map[string]bool, len(referenced))
+		for _, key := range referenced {
+			used[key] = true
+		}
+
+		for _, key := range batch {
+			if used[key] {
+				continue
+			}
+			removed, err := deleteIfOrphaned(key, query)
+			if err != nil {
+				log.Printf("Failed to delete orphaned file %s: %v", key, err)
+				continue
+			}
+			if removed {
+				deleted++
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// deleteIfOrphaned удаляет файл, если ссылок на него по-прежнему нет. Между первой
+// проверкой и удалением тот же файл могли загрузить заново и сослаться на него,
+// поэтому ссылки проверяются еще раз под блокировкой ключа, которую берет и загрузка
+func deleteIfOrphaned(key, query string) (bool, error) {
+	deleted := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := LockKeys(tx, key); err != nil {
+			return err
+		}
+		var referenced []string
+		if err := tx.Raw(query, []string{key}).Scan(&referenced).Error; err != nil {
+			return err
+		}
+		if len(referenced) > 0 {
+			return nil
+		}
+		if err := Default.Delete(key); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// LockKeys блокирует ключи файлов до конца транзакции tx. Под этой блокировкой
+// загружаются файлы с ключами по содержимому и записываются ссылки на них,
+// чтобы очистка не удалила файл, на который вот-вот сошлются
+func LockKeys(tx *gorm.DB, keys ...string) error {
+	// Одинаковый порядок блокировок исключает взаимоблокировку двух загрузок
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
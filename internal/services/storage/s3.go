@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsignedPayload - тело запроса не участвует в подписи, поэтому его можно
+// передавать потоком, не вычисляя хеш заранее. Поддерживается S3 и MinIO
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Options - параметры подключения к S3-совместимому хранилищу
+type S3Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	PublicURL string
+	Timeout   time.Duration
+}
+
+// S3Store хранит файлы в bucket S3-совместимого хранилища. Запросы подписываются
+// AWS Signature Version 4, поэтому драйвер работает и с AWS, и с MinIO
+type S3Store struct {
+	options  S3Options
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// S3Error - неуспешный ответ хранилища
+type S3Error struct {
+	Status  int
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3 responded with status %d: %s %s", e.Status, e.Code, e.Message)
+}
+
+func NewS3Store(options S3Options) (*S3Store, error) {
+	if options.Bucket == "" || options.AccessKey == "" || options.SecretKey == "" {
+		return nil, errors.New("s3 storage requires bucket, access key and secret key")
+	}
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", options.Endpoint)
+	}
+	return &S3Store{
+		options:  options,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: options.Timeout},
+	}, nil
+}
+
+// objectURL - адрес объекта (или bucket при пустом key) в выбранном стиле адресации
+func (s *S3Store) objectURL(key string, query url.Values) *url.URL {
+	target := *s.endpoint
+	path := "/" + key
+	if s.options.PathStyle {
+		path = strings.TrimSuffix("/"+s.options.Bucket+path, "/")
+	} else {
+		target.Host = s.options.Bucket + "." + target.Host
+	}
+	target.Path = path
+	target.RawPath = uriEncode(path, false)
+	target.RawQuery = canonicalQuery(query)
+	return &target
+}
+
+func (s *S3Store) Put(key string, body io.Reader, size int64, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key, nil).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Get(key string) (io.ReadCloser, *Object, error) {
+	if !ValidKey(key) {
+		return nil, nil, ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key, nil).String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object := &Object{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		object.ModTime = modTime
+	}
+	if object.ContentType == "" {
+		object.ContentType = "application/octet-stream"
+	}
+	return resp.Body, object, nil
+}
+
+func (s *S3Store) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key, nil).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// List постранично читает ListObjectsV2
+func (s *S3Store) List(prefix string, fn func(Object) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := http.NewRequest(http.MethodGet, s.objectURL("", query).String(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+
+		var page struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Contents {
+			if err := fn(Object{Key: item.Key, Size: item.Size, ModTime: item.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *S3Store) URL(key string) string {
+	return s.options.PublicURL + "/" + key
+}
+
+// SignedURL - presigned GET-ссылка, ее проверяет само хранилище
+func (s *S3Store) SignedURL(key string, ttl time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return s.presign(http.MethodGet, key, ttl, time.Now()), nil
+}
+
+func (s *S3Store) presign(method, key string, ttl time.Duration, now time.Time) string {
+	now = now.UTC()
+	scope := s.scope(now)
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.options.AccessKey + "/" + scope},
+		"X-Amz-Date":          {now.Format("20060102T150405Z")},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	target := s.objectURL(key, query)
+	header := http.Header{}
+	canonical := canonicalRequest(method, target, header, target.Host, []string{"host"}, unsignedPayload)
+	query.Set("X-Amz-Signature", s.signature(now, scope, canonical))
+	target.RawQuery = canonicalQuery(query)
+	return target.String()
+}
+
+// do подписывает и выполняет запрос. Ответ 404 превращается в ErrNotFound, прочие ошибки - в S3Error
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	apiErr := &S3Error{Status: resp.StatusCode}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	_ = xml.Unmarshal(raw, apiErr)
+	return nil, apiErr
+}
+
+// sign добавляет заголовок Authorization. Подписываются host, content-type и x-amz-*
+func (s *S3Store) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signed := []string{"host"}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			signed = append(signed, lower)
+		}
+	}
+	sort.Strings(signed)
+
+	scope := s.scope(now)
+	canonical := canonicalRequest(req.Method, req.URL, req.Header, req.URL.Host, signed, unsignedPayload)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.options.AccessKey, scope, strings.Join(signed, ";"), s.signature(now, scope, canonical)))
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.options.Region + "/s3/aws4_request"
+}
+
+// signature - подпись канонического запроса ключом, производным от секрета, даты и региона
+func (s *S3Store) signature(now time.Time, scope, canonical string) string {
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalRequest(method string, target *url.URL, header http.Header, host string, signed []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signed {
+		value := header.Get(name)
+		if name == "host" {
+			value = host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.Join([]string{
+		method,
+		path,
+		target.RawQuery,
+		headers.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalQuery - параметры, отсортированные по имени и закодированные по правилам SigV4
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode кодирует все, кроме A-Z a-z 0-9 - _ . ~ (и "/" в пути). В отличие от
+// url.QueryEscape пробел становится %20, а не "+"
+func uriEncode(value string, encodeSlash bool) string {
+	var result strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			result.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			result.WriteByte(b)
+		default:
+			fmt.Fprintf(&result, "%%%02X", b)
+		}
+	}
+	return result.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+)
+
+// ErrNotFound - файла с таким ключом нет
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey - ключ с недопустимыми символами или попыткой выйти из хранилища
+var ErrInvalidKey = errors.New("invalid object key")
+
+// ErrInvalidSignature - подпись временной ссылки не подходит или срок ссылки истек
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+
+// PrivatePrefix - файлы с этим префиксом отдаются только по подписанной ссылке
+const PrivatePrefix = "private/"
+
+// Object - метаданные сохраненного файла
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Store - хранилище файлов. Ключ - относительный путь вида avatars/3f/3fa9....jpg
+type Store interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	// Get возвращает содержимое и метаданные; ErrNotFound, если файла нет
+	Get(key string) (io.ReadCloser, *Object, error)
+	// Delete не считает ошибкой отсутствие файла
+	Delete(key string) error
+	// List вызывает fn для каждого файла с ключом, начинающимся с prefix
+	List(prefix string, fn func(Object) error) error
+	// URL - публичный адрес файла
+	URL(key string) string
+	// SignedURL - временная ссылка на файл, в том числе на закрытый
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// Default - хранилище, выбранное в STORAGE_DRIVER
+var Default Store
+
+// Init создает хранилище по настройкам окружения
+func Init() error {
+	cfg := config.StorageConfig
+	switch cfg.Driver {
+	case "local":
+		store, err := NewLocalStore(cfg.LocalDir, cfg.PublicURL)
+		if err != nil {
+			return err
+		}
+		Default = store
+	case "s3":
+		store, err := NewS3Store(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+			PublicURL: cfg.PublicURL,
+			Timeout:   cfg.S3Timeout,
+		})
+		if err != nil {
+			return err
+		}
+		Default = store
+	default:
+		return fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+	return nil
+}
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._/-]*$`)
+
+// ValidKey - безопасен ли ключ: без "..", абсолютных путей и служебных символов
+func ValidKey(key string) bool {
+	if len(key) > 512 || !keyPattern.MatchString(key) {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentKey - ключ по хешу содержимого: <prefix>/<2 символа хеша>/<хеш><ext>.
+// Одинаковые файлы хранятся один раз, а файл по ключу никогда не меняется,
+// поэтому его можно кешировать навсегда. Удалять такие файлы напрямую нельзя -
+// на них могут ссылаться другие записи, их убирает очистка сирот
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return strings.TrimRight(prefix, "/") + "/" + hash[:2] + "/" + hash + ext
+}
+
+// Sign - подпись временной ссылки драйвера local
+func Sign(key string, expires time.Time) string {
+	mac := hmac.New(sha256.New, config.SigningConfig.StorageURL)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature проверяет параметры expires и signature временной ссылки
+func VerifySignature(key, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+	expected := Sign(key, time.Unix(unix, 0))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
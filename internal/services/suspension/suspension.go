@@ -0,0 +1,70 @@
+package suspension
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Error - аккаунт заблокирован модератором
+type Error struct {
+	Until     *time.Time // nil для бессрочной блокировки
+	Permanent bool
+	Reason    *string
+}
+
+func (e *Error) Error() string {
+	if e.Permanent {
+		return "account is banned"
+	}
+	return fmt.Sprintf("account is suspended until %s", e.Until.Format(time.RFC3339))
+}
+
+// Details - детали ошибки account_suspended для клиента
+func (e *Error) Details() fiber.Map {
+	details := fiber.Map{
+		"permanent": e.Permanent,
+	}
+	if e.Until != nil {
+		details["suspended_until"] = e.Until
+	}
+	if e.Reason != nil {
+		details["reason"] = *e.Reason
+	}
+	return details
+}
+
+// Of возвращает *Error, если блокировка пользователя действует сейчас
+func Of(user *models.User) error {
+	if !user.IsSuspended(time.Now()) {
+		return nil
+	}
+	if user.BannedAt != nil {
+		return &Error{Permanent: true, Reason: user.SuspensionReason}
+	}
+	return &Error{Until: user.SuspendedUntil, Reason: user.SuspensionReason}
+}
+
+// Check загружает статус блокировки пользователя из БД
+func Check(userID uuid.UUID) error {
+	var user models.User
+	if err := database.DB.
+		Select("id", "suspended_until", "banned_at", "suspension_reason").
+		Where("id = ?", userID).
+		First(&user).Error; err != nil {
+		return fmt.Errorf("user not found")
+	}
+	return Of(&user)
+}
+
+// Users - подзапрос ID пользователей с действующей блокировкой
+func Users() *gorm.DB {
+	return database.DB.Model(&models.User{}).
+		Select("id").
+		Where("banned_at IS NOT NULL OR suspended_until > ?", time.Now())
+}
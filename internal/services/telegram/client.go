@@ -0,0 +1,171 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/duker221/teamly/internal/config"
+)
+
+// ErrDisabled - бот не настроен (нет TELEGRAM_BOT_TOKEN)
+var ErrDisabled = errors.New("telegram bot is not configured")
+
+// Типы Bot API - только поля, которые использует интеграция
+
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type User struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// apiResponse - общий конверт ответа Bot API
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+	ErrorCode   int             `json:"error_code"`
+}
+
+// APIError - ошибка, которую вернул Bot API
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+var (
+	clientOnce sync.Once
+	client     *http.Client
+)
+
+func httpClient() *http.Client {
+	clientOnce.Do(func() {
+		client = &http.Client{Timeout: config.TelegramConfig.Timeout}
+	})
+	return client
+}
+
+// call вызывает метод Bot API и раскладывает result в out (если out не nil)
+func call(method string, params any, out any) error {
+	cfg := config.TelegramConfig
+	if !cfg.Enabled() {
+		return ErrDisabled
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/bot%s/%s", cfg.APIURL, cfg.BotToken, method)
+	resp, err := httpClient().Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// В тексте ошибки net/http есть URL с токеном - не отдаем его в логи
+		return fmt.Errorf("telegram %s: request failed", method)
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram %s: invalid response (status %d)", method, resp.StatusCode)
+	}
+	if !result.OK {
+		return &APIError{Method: method, Code: result.ErrorCode, Description: result.Description}
+	}
+	if out != nil {
+		return json.Unmarshal(result.Result, out)
+	}
+	return nil
+}
+
+// SendMessage отправляет сообщение в чат; keyboard может быть nil
+func SendMessage(chatID int64, text string, keyboard *InlineKeyboardMarkup) error {
+	params := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+	return call("sendMessage", params, nil)
+}
+
+// EditMessageText заменяет текст сообщения и убирает кнопки
+func EditMessageText(chatID, messageID int64, text string) error {
+	return call("editMessageText", map[string]any{
+		"chat_id":                  chatID,
+		"message_id":               messageID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+// AnswerCallbackQuery закрывает "часики" на нажатой кнопке и показывает text
+func AnswerCallbackQuery(callbackID, text string) error {
+	return call("answerCallbackQuery", map[string]any{
+		"callback_query_id": callbackID,
+		"text":              text,
+	}, nil)
+}
+
+// SetWebhook регистрирует адрес, на который Telegram присылает обновления
+func SetWebhook(url, secret string) error {
+	params := map[string]any{
+		"url":             url,
+		"allowed_updates": []string{"message", "callback_query"},
+	}
+	if secret != "" {
+		params["secret_token"] = secret
+	}
+	return call("setWebhook", params, nil)
+}
+
+// IsChatUnavailable - пользователь заблокировал бота или удалил чат
+func IsChatUnavailable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.Code == http.StatusForbidden ||
+		apiErr.Code == http.StatusBadRequest && apiErr.Description == "Bad Request: chat not found")
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidLinkCode - код не найден, уже использован или истек
+var ErrInvalidLinkCode = errors.New("invalid or expired link code")
+
+// linkCodeLength - байт случайности в коде; в hex это 32 символа,
+// что укладывается в лимит 64 символа параметра /start
+const linkCodeLength = 16
+
+// CreateLinkCode выдает пользователю новый одноразовый код, отменяя предыдущие.
+// В базе хранится только хеш кода
+func CreateLinkCode(db *gorm.DB, userID uuid.UUID) (string, time.Time, error) {
+	raw := make([]byte, linkCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	code := hex.EncodeToString(raw)
+	expiresAt := time.Now().Add(config.TelegramConfig.LinkCodeTTL)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TelegramLinkCode{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TelegramLinkCode{
+			UserID:    userID,
+			Code:      hashCode(code),
+			ExpiresAt: expiresAt,
+		}).Error
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return code, expiresAt, nil
+}
+
+// DeepLink - ссылка, открывающая бота с уже подставленным кодом; пусто, если имя бота не задано
+func DeepLink(code string) string {
+	if config.TelegramConfig.BotUsername == "" {
+		return ""
+	}
+	return "https://t.me/" + config.TelegramConfig.BotUsername + "?start=" + code
+}
+
+// Link привязывает чат к владельцу кода. Если чат был привязан к другому аккаунту,
+// привязка переносится: один чат получает уведомления только одного пользователя
+func Link(db *gorm.DB, code string, chatID int64, username string) (*models.User, error) {
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var linkCode models.TelegramLinkCode
+		if err := tx.Where("code = ?", hashCode(code)).First(&linkCode).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidLinkCode
+			}
+			return err
+		}
+		if !linkCode.IsValid() {
+			return ErrInvalidLinkCode
+		}
+
+		now := time.Now()
+		if err := tx.Model(&linkCode).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).
+			Where("telegram_chat_id = ? AND id <> ?", chatID, linkCode.UserID).
+			Updates(map[string]interface{}{"telegram_chat_id": nil, "telegram_linked_at": nil}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&user, linkCode.UserID).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"telegram_chat_id": chatID, "telegram_linked_at": now}
+		// Контакт в профиле заполняем, только если пользователь не указал его сам
+		if username != "" && (user.Telegram == nil || *user.Telegram == "") {
+			updates["telegram"] = "@" + username
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Unlink отвязывает Telegram от пользователя
+func Unlink(db *gorm.DB, userID uuid.UUID) error {
+	return db.Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{"telegram_chat_id": nil, "telegram_linked_at": nil}).Error
+}
+
+// UserByChat - пользователь, к которому привязан чат
+func UserByChat(db *gorm.DB, chatID int64) (*models.User, error) {
+	var user models.User
+	if err := db.Where("telegram_chat_id = ?", chatID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func hashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
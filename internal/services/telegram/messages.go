@@ -0,0 +1,19 @@
+package telegram
+
+import (
+	"embed"
+
+	"github.com/duker221/teamly/internal/i18n"
+)
+
+// Тексты бота: по JSON-файлу на язык, значения - text/template строки
+//
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var locales = i18n.MustLoad("telegram", localeFS, nil)
+
+// T возвращает текст бота на языке locale; если перевода нет - на языке по умолчанию
+func T(locale, key string, data any) string {
+	return locales.T(locale, key, data)
+}
@@ -0,0 +1,171 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// backlogLimit - уведомления старше этого в Telegram уже не отправляются
+const backlogLimit = 24 * time.Hour
+
+// Действия inline-кнопок под уведомлением о новом отклике: "accept:<id отклика>"
+const (
+	ActionAccept = "accept"
+	ActionReject = "reject"
+)
+
+// ParseCallbackData разбирает данные нажатой кнопки
+func ParseCallbackData(data string) (string, uuid.UUID, bool) {
+	action, rawID, found := strings.Cut(data, ":")
+	if !found || (action != ActionAccept && action != ActionReject) {
+		return "", uuid.Nil, false
+	}
+	responseID, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, false
+	}
+	return action, responseID, true
+}
+
+// StartNotifier периодически отправляет новые уведомления в привязанные чаты
+func StartNotifier() {
+	interval := config.TelegramConfig.PollInterval
+	if !config.TelegramConfig.Enabled() || interval <= 0 {
+		log.Println("Telegram notifications disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			sendPending()
+			<-ticker.C
+		}
+	}()
+}
+
+// RegisterWebhook сообщает Telegram адрес для обновлений, если он задан
+func RegisterWebhook() {
+	cfg := config.TelegramConfig
+	if !cfg.Enabled() || cfg.WebhookURL == "" {
+		return
+	}
+	if !cfg.WebhookEnabled() {
+		log.Println("Warning: TELEGRAM_WEBHOOK_SECRET is empty, Telegram webhook is not registered")
+		return
+	}
+	if err := SetWebhook(cfg.WebhookURL, cfg.WebhookSecret); err != nil {
+		log.Printf("Warning: failed to register Telegram webhook: %v", err)
+	}
+}
+
+func sendPending() {
+	now := time.Now()
+
+	// Уведомления пользователя попадают в Telegram, только если созданы после привязки
+	var pending []models.Notification
+	if err := database.DB.
+		Preload("Actor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname")
+		}).
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title")
+		}).
+		Where("telegram_sent_at IS NULL AND read_at IS NULL AND type IN ? AND created_at > ?",
+			models.TelegramNotificationTypes, now.Add(-backlogLimit)).
+		Where("EXISTS (SELECT 1 FROM users WHERE users.id = notifications.user_id AND users.telegram_chat_id IS NOT NULL AND users.telegram_linked_at <= notifications.created_at)").
+		Order("created_at ASC").
+		Find(&pending).Error; err != nil {
+		log.Printf("Failed to load pending Telegram notifications: %v", err)
+		return
+	}
+
+	users := make(map[uuid.UUID]*models.User)
+	for _, notification := range pending {
+		user, ok := users[notification.UserID]
+		if !ok {
+			user = &models.User{}
+			if err := database.DB.Select("id", "locale", "telegram_chat_id").First(user, notification.UserID).Error; err != nil {
+				log.Printf("Failed to load user %s for Telegram notification: %v", notification.UserID, err)
+				continue
+			}
+			users[notification.UserID] = user
+		}
+		if user.TelegramChatID == nil {
+			// Бот заблокирован на предыдущем уведомлении этого пользователя
+			continue
+		}
+
+		text, keyboard := formatNotification(user.Locale, notification)
+		err := SendMessage(*user.TelegramChatID, text, keyboard)
+		if IsChatUnavailable(err) {
+			log.Printf("Telegram chat of user %s is unavailable, unlinking: %v", user.ID, err)
+			if err := Unlink(database.DB, user.ID); err != nil {
+				log.Printf("Failed to unlink Telegram of user %s: %v", user.ID, err)
+			}
+			user.TelegramChatID = nil
+			continue
+		}
+		if err != nil {
+			// Повторим на следующем проходе
+			log.Printf("Failed to send Telegram notification %s: %v", notification.ID, err)
+			continue
+		}
+
+		if err := database.DB.Model(&notification).Update("telegram_sent_at", now).Error; err != nil {
+			log.Printf("Failed to mark Telegram notification %s as sent: %v", notification.ID, err)
+		}
+	}
+}
+
+// formatNotification - текст сообщения и, для нового отклика, кнопки принять/отклонить
+func formatNotification(locale string, n models.Notification) (string, *InlineKeyboardMarkup) {
+	actor := T(locale, "notification.someone", nil)
+	if n.Actor != nil {
+		actor = n.Actor.Nickname
+	}
+	title := ""
+	url := config.FrontendURL()
+	if n.Application != nil {
+		title = n.Application.Title
+		url = fmt.Sprintf("%s/applications/%s", config.FrontendURL(), n.Application.ID)
+	}
+	data := map[string]any{"Actor": actor, "Title": title}
+
+	switch n.Type {
+	case models.NotificationNewResponse:
+		text := T(locale, "notification.new_response", data) + "\n" + url
+		if n.EntityID == nil {
+			return text, nil
+		}
+		return text, &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: T(locale, "button.accept", nil), CallbackData: ActionAccept + ":" + n.EntityID.String()},
+			{Text: T(locale, "button.reject", nil), CallbackData: ActionReject + ":" + n.EntityID.String()},
+		}}}
+	case models.NotificationResponseAccepted, models.NotificationResponseRejected:
+		return T(locale, "notification."+string(n.Type), data) + "\n" + url, nil
+	default:
+		text := T(locale, "notification.new_message", data)
+		if count, ok := n.Data["count"].(float64); ok && count > 1 {
+			data["Count"] = int(count)
+			text = T(locale, "notification.new_messages", data)
+		}
+		if preview, ok := n.Data["preview"].(string); ok && preview != "" {
+			text += "\n" + preview
+		}
+		url = config.FrontendURL() + "/messages"
+		if n.EntityID != nil {
+			url = fmt.Sprintf("%s/messages/%s", config.FrontendURL(), n.EntityID)
+		}
+		return text + "\n" + url, nil
+	}
+}
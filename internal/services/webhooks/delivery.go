@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/database"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/duker221/teamly/internal/services/outbox"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Заголовки запроса к вебхуку. Подпись: hex(HMAC-SHA256(secret, timestamp + "." + body))
+const (
+	HeaderEvent     = "X-Teamly-Event"
+	HeaderDelivery  = "X-Teamly-Delivery"
+	HeaderTimestamp = "X-Teamly-Timestamp"
+	HeaderSignature = "X-Teamly-Signature"
+)
+
+// maxResponseBody - сколько байт ответа получателя сохраняется в журнале
+const maxResponseBody = 2048
+
+var errPrivateAddress = errors.New("private network addresses are not allowed")
+
+// Sign подписывает тело запроса секретом вебхука
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret генерирует ключ подписи для нового вебхука
+func NewSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// ValidateURL проверяет адрес вебхука при регистрации
+func ValidateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.New("webhook URL must be an absolute http(s) URL")
+	}
+	if config.WebhookConfig.AllowPrivateNetworks {
+		return nil
+	}
+	host := parsed.Hostname()
+	if host == "localhost" {
+		return errPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
+
+var (
+	clientOnce sync.Once
+	client     *http.Client
+)
+
+// httpClient не ходит по редиректам и, если не разрешено явно, во внутреннюю сеть:
+// адрес проверяется уже после DNS, чтобы домен не мог указывать на localhost
+func httpClient() *http.Client {
+	clientOnce.Do(func() {
+		dialer := &net.Dialer{
+			Timeout: config.WebhookConfig.Timeout,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				if config.WebhookConfig.AllowPrivateNetworks {
+					return nil
+				}
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+					return errPrivateAddress
+				}
+				return nil
+			},
+		}
+		client = &http.Client{
+			Timeout:   config.WebhookConfig.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	})
+	return client
+}
+
+// StartDeliveryWorker запускает фоновую доставку событий и поиск истекших заявок
+func StartDeliveryWorker() {
+	interval := config.WebhookConfig.PollInterval
+	if interval <= 0 {
+		log.Println("Webhook delivery worker disabled")
+		return
+	}
+
+	deliveries := outbox.Queue[models.WebhookDelivery]{
+		Name:      "Webhook delivery",
+		BatchSize: config.WebhookConfig.BatchSize,
+		Ready: func(tx *gorm.DB, now time.Time) *gorm.DB {
+			return tx.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now)
+		},
+		Process: deliverBatch,
+	}
+	outbox.Every(interval, deliveries.Drain)
+
+	if config.WebhookConfig.ExpiryCheckInterval > 0 {
+		outbox.Every(config.WebhookConfig.ExpiryCheckInterval, dispatchExpiredApplications)
+	}
+}
+
+// deliverBatch отправляет пачку доставок. Доставки отключенных и удаленных вебхуков
+// сразу уходят в dead letter
+func deliverBatch(tx *gorm.DB, batch []models.WebhookDelivery, _ time.Time) error {
+	for i := range batch {
+		var hook models.Webhook
+		if err := tx.First(&hook, batch[i].WebhookID).Error; err != nil || !hook.IsActive {
+			message := "webhook is disabled"
+			batch[i].Status = models.WebhookDeliveryDead
+			batch[i].LastError = &message
+			continue
+		}
+		deliver(&hook, &batch[i])
+	}
+	return nil
+}
+
+// deliver делает одну попытку доставки и планирует следующую при ошибке
+func deliver(hook *models.Webhook, delivery *models.WebhookDelivery) {
+	err := attempt(hook, delivery)
+	if err == nil {
+		return
+	}
+
+	if delivery.Attempts >= config.WebhookConfig.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryDead
+		log.Printf("Webhook delivery %s (%s) to %s moved to dead letter after %d attempts: %v", delivery.ID, delivery.Event, hook.URL, delivery.Attempts, err)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(outbox.RetryDelay(delivery.Attempts, config.WebhookConfig.RetryBase, config.WebhookConfig.RetryMax))
+	log.Printf("Webhook delivery %s (%s) to %s failed (attempt %d), retry at %s: %v", delivery.ID, delivery.Event, hook.URL, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), err)
+}
+
+// attempt отправляет подписанный запрос и записывает ответ получателя.
+// Успехом считается любой 2xx
+func attempt(hook *models.Webhook, delivery *models.WebhookDelivery) error {
+	delivery.Attempts++
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return recordFailure(delivery, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Teamly-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return recordFailure(delivery, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	responseBody := string(body)
+	delivery.ResponseStatus = &resp.StatusCode
+	delivery.ResponseBody = &responseBody
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return recordFailure(delivery, fmt.Errorf("unexpected response status %d", resp.StatusCode))
+	}
+
+	now := time.Now()
+	delivery.Status = models.WebhookDeliveryDelivered
+	delivery.DeliveredAt = &now
+	delivery.LastError = nil
+	return nil
+}
+
+func recordFailure(delivery *models.WebhookDelivery, err error) error {
+	message := err.Error()
+	delivery.LastError = &message
+	return err
+}
+
+// Ping сразу отправляет на вебхук тестовое событие и возвращает запись журнала.
+// Повторов нет - результат нужен пользователю сейчас
+func Ping(db *gorm.DB, hook *models.Webhook) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(Envelope{
+		ID:        uuid.New(),
+		Event:     models.WebhookPing,
+		CreatedAt: time.Now(),
+		Data: map[string]any{
+			"webhook_id": hook.ID,
+			"events":     hook.Events,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := models.WebhookDelivery{
+		WebhookID: hook.ID,
+		Event:     models.WebhookPing,
+		Payload:   payload,
+		Status:    models.WebhookDeliveryPending,
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		return nil, err
+	}
+
+	if attempt(hook, &delivery) != nil {
+		delivery.Status = models.WebhookDeliveryDead
+	}
+	if err := db.Save(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// dispatchExpiredApplications отправляет application.closed для заявок,
+// у которых недавно закончился прайм-тайм и о закрытии еще не сообщали
+func dispatchExpiredApplications() {
+	now := time.Now()
+
+	var expired []models.GameApplication
+	if err := database.DB.Select("id").
+		Where("is_active = ? AND is_full = ?", true, false).
+		Where("prime_time_end <= ? AND prime_time_end > ?", now, now.Add(-24*time.Hour)).
+		Where("NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.application_id = game_applications.id AND d.event = ?)", models.WebhookApplicationClosed).
+		Find(&expired).Error; err != nil {
+		log.Printf("Failed to find expired applications for webhooks: %v", err)
+		return
+	}
+
+	for _, application := range expired {
+		if err := DispatchApplication(database.DB, models.WebhookApplicationClosed, application.ID, "expired"); err != nil {
+			log.Printf("Failed to dispatch application.closed for %s: %v", application.ID, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/duker221/teamly/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Envelope - тело запроса к вебхуку. ID общий для всех получателей события
+type Envelope struct {
+	ID        uuid.UUID           `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      any                 `json:"data"`
+}
+
+// DispatchApplication ставит в очередь событие заявки. reason заполняется
+// для application.closed: deleted, full, expired, hidden, unlisted
+func DispatchApplication(db *gorm.DB, event models.WebhookEvent, applicationID uuid.UUID, reason string) error {
+	application, err := loadApplication(db, applicationID)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]any{"application": applicationData(application)}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	return dispatch(db, event, application, data, isPublic(application))
+}
+
+// DispatchUnlisted ставит в очередь application.closed с причиной unlisted, когда автор
+// убрал публичную заявку из ленты. Получатели - прежняя публичная аудитория:
+// по новому состоянию заявки о закрытии узнали бы только автор и админы
+func DispatchUnlisted(db *gorm.DB, applicationID uuid.UUID) error {
+	application, err := loadApplication(db, applicationID)
+	if err != nil {
+		return err
+	}
+
+	return dispatch(db, models.WebhookApplicationClosed, application, map[string]any{
+		"application": applicationData(application),
+		"reason":      "unlisted",
+	}, true)
+}
+
+// DispatchResponse ставит в очередь событие отклика на заявку
+func DispatchResponse(db *gorm.DB, event models.WebhookEvent, responseID uuid.UUID) error {
+	var response models.ApplicationResponse
+	if err := db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "nickname")
+	}).First(&response, responseID).Error; err != nil {
+		return err
+	}
+
+	application, err := loadApplication(db, response.ApplicationID)
+	if err != nil {
+		return err
+	}
+
+	return dispatch(db, event, application, map[string]any{
+		"application": applicationData(application),
+		"response": map[string]any{
+			"id":         response.ID,
+			"status":     response.Status,
+			"created_at": response.CreatedAt,
+			"player": map[string]any{
+				"id":       response.User.ID,
+				"nickname": response.User.Nickname,
+			},
+		},
+	}, false)
+}
+
+// dispatch создает по доставке на каждый подходящий вебхук через db.
+// Внутри транзакции событие уйдет, только если изменение закоммитится
+func dispatch(db *gorm.DB, event models.WebhookEvent, application *models.GameApplication, data map[string]any, public bool) error {
+	hooks, err := subscribers(db, event, application, public)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Event:         event,
+			ApplicationID: &application.ID,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+		})
+	}
+	return db.Create(&deliveries).Error
+}
+
+// subscribers - активные вебхуки, подписанные на событие. О публичных заявках узнают все,
+// об откликах и скрытых заявках - только автор заявки и админы
+func subscribers(db *gorm.DB, event models.WebhookEvent, application *models.GameApplication, public bool) ([]models.Webhook, error) {
+	query := db.Where("is_active = ? AND events @> ?::jsonb", true, fmt.Sprintf(`[%q]`, event)).
+		Where("game_id IS NULL OR game_id = ?", application.GameId)
+
+	isApplicationEvent := event == models.WebhookApplicationCreated ||
+		event == models.WebhookApplicationUpdated ||
+		event == models.WebhookApplicationClosed
+	if !public || !isApplicationEvent {
+		query = query.Where("owner_id = ? OR owner_id IN (?)", application.UserId,
+			db.Session(&gorm.Session{NewDB: true}).Model(&models.User{}).Select("id").Where("role = ?", models.RoleAdmin))
+	}
+
+	var hooks []models.Webhook
+	err := query.Find(&hooks).Error
+	return hooks, err
+}
+
+// isPublic - видна ли заявка в публичной ленте. Заявки заблокированного автора
+// из ленты пропадают, поэтому и события о них получают только автор и админы
+func isPublic(application *models.GameApplication) bool {
+	return application.Visibility == models.VisibilityPublic &&
+		!application.IsHidden &&
+		!application.User.IsSuspended(time.Now())
+}
+
+func loadApplication(db *gorm.DB, applicationID uuid.UUID) (*models.GameApplication, error) {
+	var application models.GameApplication
+	if err := db.
+		Preload("Game").
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "nickname", "suspended_until", "banned_at")
+		}).
+		First(&application, applicationID).Error; err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// applicationData - публичное представление заявки в событиях
+func applicationData(application *models.GameApplication) map[string]any {
+	slotsLeft := application.MaxPlayers - application.AcceptedPlayers
+	if slotsLeft < 0 {
+		slotsLeft = 0
+	}
+	return map[string]any{
+		"id":               application.ID,
+		"title":            application.Title,
+		"description":      application.Description,
+		"platform":         application.Platform,
+		"visibility":       application.Visibility,
+		"with_voice_chat":  application.WithVoiceChat,
+		"min_players":      application.MinPlayers,
+		"max_players":      application.MaxPlayers,
+		"accepted_players": application.AcceptedPlayers,
+		"slots_left":       slotsLeft,
+		"is_active":        application.IsActive,
+		"is_full":          application.IsFull,
+		"prime_time_start": application.PrimeTimeStart,
+		"prime_time_end":   application.PrimeTimeEnd,
+		"url":              fmt.Sprintf("%s/applications/%s", config.FrontendURL(), application.ID),
+		"game": map[string]any{
+			"id":   application.Game.ID,
+			"name": application.Game.Name,
+			"slug": application.Game.Slug,
+		},
+		"author": map[string]any{
+			"id":       application.User.ID,
+			"nickname": application.User.Nickname,
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "strings"
+
+// DefaultLocale - язык по умолчанию для писем и сообщений API
+const DefaultLocale = "ru"
+
+// SupportedLocales - языки, для которых есть переводы
+var SupportedLocales = []string{"ru", "en"}
+
+// NormalizeLocale приводит "en-US", "EN" и т.п. к поддерживаемому языку или DefaultLocale
+func NormalizeLocale(locale string) string {
+	if locale, ok := matchLocale(locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// IsSupportedLocale - есть ли перевод для языка
+func IsSupportedLocale(locale string) bool {
+	_, ok := matchLocale(locale)
+	return ok
+}
+
+// LocaleFromAcceptLanguage выбирает первый поддерживаемый язык из заголовка Accept-Language.
+// Веса q учитываются порядком - браузеры перечисляют языки по убыванию приоритета
+func LocaleFromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if locale, ok := matchLocale(tag); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+func matchLocale(tag string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	base, _, _ = strings.Cut(base, "_")
+	for _, supported := range SupportedLocales {
+		if base == supported {
+			return supported, true
+		}
+	}
+	return "", false
+}
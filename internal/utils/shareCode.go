@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+// shareSignatureLength - сколько байт HMAC оставляем в коде (коротко, но не перебираемо)
+const shareSignatureLength = 12
+
+// NewShareNonce генерирует новую соль для share-кода заявки
+func NewShareNonce() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// GenerateShareCode подписывает ID заявки вместе с ее солью.
+// Формат: base64url(uuid) + "." + base64url(hmac)
+func GenerateShareCode(applicationID uuid.UUID, nonce string) string {
+	return base64.RawURLEncoding.EncodeToString(applicationID[:]) + "." +
+		base64.RawURLEncoding.EncodeToString(shareSignature(applicationID, nonce))
+}
+
+// ParseShareCode извлекает ID заявки из кода без проверки подписи
+func ParseShareCode(code string) (uuid.UUID, error) {
+	idPart, _, found := strings.Cut(code, ".")
+	if !found {
+		return uuid.Nil, fmt.Errorf("invalid share code format")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(idPart)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid share code: %v", err)
+	}
+
+	return uuid.FromBytes(raw)
+}
+
+// VerifyShareCode проверяет, что код выдан для этой заявки с текущей солью
+func VerifyShareCode(code string, applicationID uuid.UUID, nonce string) bool {
+	if nonce == "" {
+		return false
+	}
+	expected := GenerateShareCode(applicationID, nonce)
+	return hmac.Equal([]byte(code), []byte(expected))
+}
+
+func shareSignature(applicationID uuid.UUID, nonce string) []byte {
+	mac := hmac.New(sha256.New, config.SigningConfig.ShareCode)
+	mac.Write(applicationID[:])
+	mac.Write([]byte(nonce))
+	return mac.Sum(nil)[:shareSignatureLength]
+}
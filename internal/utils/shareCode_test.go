@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+func withShareKey(t *testing.T, key string) {
+	t.Helper()
+	previous := config.SigningConfig
+	config.SigningConfig.ShareCode = []byte(key)
+	t.Cleanup(func() { config.SigningConfig = previous })
+}
+
+func TestParseShareCode(t *testing.T) {
+	withShareKey(t, "test-share-key")
+	applicationID := uuid.New()
+	code := GenerateShareCode(applicationID, "nonce")
+	idPart, signaturePart, _ := strings.Cut(code, ".")
+
+	cases := map[string]struct {
+		code    string
+		want    uuid.UUID
+		wantErr bool
+	}{
+		"valid":              {code: code, want: applicationID},
+		"id only":            {code: idPart + ".", want: applicationID},
+		"no separator":       {code: idPart, wantErr: true},
+		"empty":              {code: "", wantErr: true},
+		"bad base64":         {code: "!!!." + signaturePart, wantErr: true},
+		"padded base64":      {code: base64.URLEncoding.EncodeToString(applicationID[:]) + "." + signaturePart, wantErr: true},
+		"short id":           {code: base64.RawURLEncoding.EncodeToString(applicationID[:8]) + "." + signaturePart, wantErr: true},
+		"signature as id":    {code: signaturePart + "." + idPart, wantErr: true},
+		"extra part is kept": {code: code + ".extra", want: applicationID},
+	}
+	for name, tc := range cases {
+		got, err := ParseShareCode(tc.code)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: ParseShareCode(%q) = %v, want error", name, tc.code, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%s: ParseShareCode(%q) = %v, %v; want %v", name, tc.code, got, err, tc.want)
+		}
+	}
+}
+
+func TestVerifyShareCode(t *testing.T) {
+	applicationID := uuid.New()
+
+	withShareKey(t, "other-key")
+	otherKeyCode := GenerateShareCode(applicationID, "nonce")
+
+	config.SigningConfig.ShareCode = []byte("test-share-key")
+	code := GenerateShareCode(applicationID, "nonce")
+	idPart, signaturePart, _ := strings.Cut(code, ".")
+	tampered := []byte(code)
+	tampered[len(tampered)-1] ^= 1
+
+	cases := map[string]struct {
+		code          string
+		applicationID uuid.UUID
+		nonce         string
+		want          bool
+	}{
+		"valid":             {code, applicationID, "nonce", true},
+		"rotated nonce":     {code, applicationID, "rotated", false},
+		"no nonce":          {code, applicationID, "", false},
+		"other application": {code, uuid.New(), "nonce", false},
+		"other key":         {otherKeyCode, applicationID, "nonce", false},
+		"tampered":          {string(tampered), applicationID, "nonce", false},
+		"missing signature": {idPart + ".", applicationID, "nonce", false},
+		"missing id":        {"." + signaturePart, applicationID, "nonce", false},
+		"trailing part":     {code + ".extra", applicationID, "nonce", false},
+		"empty":             {"", applicationID, "nonce", false},
+	}
+	for name, tc := range cases {
+		if got := VerifyShareCode(tc.code, tc.applicationID, tc.nonce); got != tc.want {
+			t.Errorf("%s: VerifyShareCode = %v, want %v", name, got, tc.want)
+		}
+	}
+}
@@ -76,7 +76,19 @@ func GetUserIDFromToken(tokenString string) (uuid.UUID, error) {
 	return userID, nil
 }
 
+// AuthErrorLocal - ключ c.Locals, под которым middleware.Identify оставляет причину,
+// по которой токен запроса не принят (например, блокировку пользователя)
+const AuthErrorLocal = "authError"
+
 func GetUserIDFromContext(c *fiber.Ctx) (uuid.UUID, error) {
+	// middleware.Identify уже проверил токен и блокировку в рамках этого запроса
+	if id, ok := c.Locals("userID").(string); ok {
+		return uuid.Parse(id)
+	}
+	if err, ok := c.Locals(AuthErrorLocal).(error); ok {
+		return uuid.Nil, err
+	}
+
 	// Сначала проверяем HTTP-only cookie
 	tokenString := c.Cookies("auth_token")
 
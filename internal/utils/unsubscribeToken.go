@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+// UnsubscribeAll - токен отписки от всех писем-уведомлений
+const UnsubscribeAll = "all"
+
+// GenerateUnsubscribeToken подписывает пару пользователь + тип события.
+// Токен бессрочный - ссылка из старого письма тоже должна работать.
+// Формат: base64url(userID:event) + "." + base64url(hmac)
+func GenerateUnsubscribeToken(userID uuid.UUID, event string) string {
+	payload := userID.String() + ":" + event
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(unsubscribeSignature(payload))
+}
+
+// ParseUnsubscribeToken проверяет подпись и возвращает пользователя и тип события
+func ParseUnsubscribeToken(token string) (uuid.UUID, string, error) {
+	payloadPart, signaturePart, found := strings.Cut(token, ".")
+	if !found {
+		return uuid.Nil, "", fmt.Errorf("invalid unsubscribe token format")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("invalid unsubscribe token: %v", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("invalid unsubscribe token: %v", err)
+	}
+	if !hmac.Equal(signature, unsubscribeSignature(string(payload))) {
+		return uuid.Nil, "", fmt.Errorf("invalid unsubscribe token signature")
+	}
+
+	userPart, event, found := strings.Cut(string(payload), ":")
+	if !found || event == "" {
+		return uuid.Nil, "", fmt.Errorf("invalid unsubscribe token payload")
+	}
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("invalid user_id format: %v", err)
+	}
+
+	return userID, event, nil
+}
+
+func unsubscribeSignature(payload string) []byte {
+	mac := hmac.New(sha256.New, config.SigningConfig.Unsubscribe)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return mac.Sum(nil)[:16]
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/duker221/teamly/internal/config"
+	"github.com/google/uuid"
+)
+
+func withUnsubscribeKey(t *testing.T, key string) {
+	t.Helper()
+	previous := config.SigningConfig
+	config.SigningConfig.Unsubscribe = []byte(key)
+	t.Cleanup(func() { config.SigningConfig = previous })
+}
+
+// signedUnsubscribePayload подписывает произвольный payload текущим ключом,
+// чтобы проверять разбор содержимого за пределами проверки подписи
+func signedUnsubscribePayload(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(unsubscribeSignature(payload))
+}
+
+func TestParseUnsubscribeToken(t *testing.T) {
+	userID := uuid.New()
+
+	withUnsubscribeKey(t, "other-key")
+	otherKeyToken := GenerateUnsubscribeToken(userID, "new_response")
+
+	config.SigningConfig.Unsubscribe = []byte("test-unsubscribe-key")
+	token := GenerateUnsubscribeToken(userID, "new_response")
+	payload, signature, _ := strings.Cut(token, ".")
+	otherEvent, _, _ := strings.Cut(GenerateUnsubscribeToken(userID, UnsubscribeAll), ".")
+
+	cases := map[string]struct {
+		token     string
+		wantEvent string
+		wantErr   bool
+	}{
+		"valid":                 {token: token, wantEvent: "new_response"},
+		"all":                   {token: GenerateUnsubscribeToken(userID, UnsubscribeAll), wantEvent: UnsubscribeAll},
+		"no separator":          {token: payload, wantErr: true},
+		"empty":                 {token: "", wantErr: true},
+		"missing signature":     {token: payload + ".", wantErr: true},
+		"missing payload":       {token: "." + signature, wantErr: true},
+		"bad payload base64":    {token: "!!!." + signature, wantErr: true},
+		"bad signature base64":  {token: payload + ".!!!", wantErr: true},
+		"padded payload":        {token: base64.URLEncoding.EncodeToString([]byte(userID.String()+":x")) + "." + signature, wantErr: true},
+		"signature of event":    {token: otherEvent + "." + signature, wantErr: true},
+		"other key":             {token: otherKeyToken, wantErr: true},
+		"trailing part":         {token: token + ".extra", wantErr: true},
+		"signed without event":  {token: signedUnsubscribePayload(userID.String()), wantErr: true},
+		"signed empty event":    {token: signedUnsubscribePayload(userID.String() + ":"), wantErr: true},
+		"signed bad user id":    {token: signedUnsubscribePayload("not-a-uuid:new_response"), wantErr: true},
+		"event keeps its colon": {token: signedUnsubscribePayload(userID.String() + ":a:b"), wantEvent: "a:b"},
+	}
+	for name, tc := range cases {
+		gotUser, gotEvent, err := ParseUnsubscribeToken(tc.token)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: ParseUnsubscribeToken(%q) = %v, %q; want error", name, tc.token, gotUser, gotEvent)
+			}
+			continue
+		}
+		if err != nil || gotUser != userID || gotEvent != tc.wantEvent {
+			t.Errorf("%s: ParseUnsubscribeToken(%q) = %v, %q, %v; want %v, %q", name, tc.token, gotUser, gotEvent, err, userID, tc.wantEvent)
+		}
+	}
+}